@@ -0,0 +1,302 @@
+package structextract
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PostgresArray holds the elements of a slice field with the array tag option,
+// it implements driver.Valuer producing a Postgres array literal, e.g. {"a","b \"c\"",NULL}
+// Nested slices are PostgresArray elements themselves
+type PostgresArray []interface{}
+
+// Value returns the Postgres array literal
+func (a PostgresArray) Value() (driver.Value, error) {
+	var sb strings.Builder
+	if err := a.write(&sb); err != nil {
+		return nil, err
+	}
+	return sb.String(), nil
+}
+
+func (a PostgresArray) write(sb *strings.Builder) error {
+	sb.WriteByte('{')
+	for i, elem := range a {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := writeArrayElem(sb, elem); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte('}')
+	return nil
+}
+
+func writeArrayElem(sb *strings.Builder, elem interface{}) error {
+	if valuer, ok := elem.(driver.Valuer); ok {
+		if _, nested := elem.(PostgresArray); !nested {
+			v, err := valuer.Value()
+			if err != nil {
+				return err
+			}
+			elem = v
+		}
+	}
+
+	switch v := elem.(type) {
+	case nil:
+		sb.WriteString("NULL")
+	case PostgresArray:
+		return v.write(sb)
+	case string:
+		writeQuoted(sb, v)
+	case []byte:
+		writeQuoted(sb, string(v))
+	case bool:
+		if v {
+			sb.WriteString("t")
+		} else {
+			sb.WriteString("f")
+		}
+	case float32:
+		writeFloat(sb, float64(v), 32)
+	case float64:
+		writeFloat(sb, v, 64)
+	case time.Time:
+		writeQuoted(sb, v.Format(time.RFC3339Nano))
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			sb.WriteString(strconv.FormatInt(rv.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			sb.WriteString(strconv.FormatUint(rv.Uint(), 10))
+		case reflect.String:
+			writeQuoted(sb, rv.String())
+		default:
+			writeQuoted(sb, fmt.Sprint(v))
+		}
+	}
+	return nil
+}
+
+// writeQuoted writes a double quoted element, escaping backslashes and double quotes
+func writeQuoted(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(s[i])
+	}
+	sb.WriteByte('"')
+}
+
+func writeFloat(sb *strings.Builder, f float64, bits int) {
+	switch {
+	case math.IsNaN(f):
+		sb.WriteString("NaN")
+	case math.IsInf(f, 1):
+		sb.WriteString("Infinity")
+	case math.IsInf(f, -1):
+		sb.WriteString("-Infinity")
+	default:
+		sb.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	}
+}
+
+// postgresArray converts a slice or an array to a PostgresArray, nested slices included
+func postgresArray(val reflect.Value) PostgresArray {
+	out := make(PostgresArray, val.Len())
+	for i := range out {
+		elem := val.Index(i)
+		for elem.Kind() == reflect.Ptr && !elem.IsNil() && !isValuer(elem) {
+			elem = elem.Elem()
+		}
+		if isList(elem) && elem.Type().Elem().Kind() != reflect.Uint8 {
+			out[i] = postgresArray(elem)
+			continue
+		}
+		if isNilValue(elem) {
+			out[i] = nil
+			continue
+		}
+		out[i] = elem.Interface()
+	}
+	return out
+}
+
+// isValuer reports whether the value writes itself with driver.Valuer
+func isValuer(val reflect.Value) bool {
+	_, ok := val.Interface().(driver.Valuer)
+	return ok
+}
+
+func isList(val reflect.Value) bool {
+	return val.Kind() == reflect.Slice || val.Kind() == reflect.Array
+}
+
+// arrayElem is a parsed element of a Postgres array literal
+type arrayElem struct {
+	value    string
+	null     bool
+	children []arrayElem // children: the elements of a nested array
+	nested   bool
+}
+
+// parsePostgresArray parses a Postgres array literal such as {"a",NULL,{1,2}}
+func parsePostgresArray(s string) ([]arrayElem, error) {
+	// skip the dimension decoration, e.g. [1:2]={a,b}
+	if strings.HasPrefix(s, "[") {
+		if i := strings.Index(s, "="); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+
+	p := arrayParser{input: s}
+	elem, err := p.array()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("unexpected %q after the array literal", p.input[p.pos:])
+	}
+	return elem.children, nil
+}
+
+type arrayParser struct {
+	input string
+	pos   int
+}
+
+func (p *arrayParser) array() (arrayElem, error) {
+	if p.pos >= len(p.input) || p.input[p.pos] != '{' {
+		return arrayElem{}, errors.New("array literal must start with {")
+	}
+	p.pos++
+
+	out := arrayElem{nested: true}
+	if p.pos < len(p.input) && p.input[p.pos] == '}' {
+		p.pos++
+		return out, nil
+	}
+	for {
+		elem, err := p.elem()
+		if err != nil {
+			return arrayElem{}, err
+		}
+		out.children = append(out.children, elem)
+
+		if p.pos >= len(p.input) {
+			return arrayElem{}, errors.New("unterminated array literal")
+		}
+		switch p.input[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return out, nil
+		default:
+			return arrayElem{}, fmt.Errorf("unexpected %q in array literal", p.input[p.pos])
+		}
+	}
+}
+
+func (p *arrayParser) elem() (arrayElem, error) {
+	if p.pos >= len(p.input) {
+		return arrayElem{}, errors.New("unterminated array literal")
+	}
+
+	switch p.input[p.pos] {
+	case '{':
+		return p.array()
+	case '"':
+		p.pos++
+		var sb strings.Builder
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			p.pos++
+			switch c {
+			case '\\':
+				if p.pos >= len(p.input) {
+					return arrayElem{}, errors.New("unterminated escape in array literal")
+				}
+				sb.WriteByte(p.input[p.pos])
+				p.pos++
+			case '"':
+				return arrayElem{value: sb.String()}, nil
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return arrayElem{}, errors.New("unterminated quoted element in array literal")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != ',' && p.input[p.pos] != '}' {
+		p.pos++
+	}
+	value := strings.TrimSpace(p.input[start:p.pos])
+	if strings.EqualFold(value, "NULL") {
+		return arrayElem{null: true}, nil
+	}
+	return arrayElem{value: value}, nil
+}
+
+// setPostgresArray parses a Postgres array literal into a slice
+func setPostgresArray(dst reflect.Value, value interface{}) error {
+	data, ok := asBytes(value)
+	if !ok {
+		return fmt.Errorf("expected an array literal string or []byte, got %T", value)
+	}
+	elems, err := parsePostgresArray(string(data))
+	if err != nil {
+		return err
+	}
+	return setArrayElems(dst, elems)
+}
+
+func setArrayElems(dst reflect.Value, elems []arrayElem) error {
+	if dst.Kind() == reflect.Ptr {
+		ptr := reflect.New(dst.Type().Elem())
+		if err := setArrayElems(ptr.Elem(), elems); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+	if dst.Kind() != reflect.Slice {
+		return fmt.Errorf("array tag option expects a slice, got %s", dst.Type())
+	}
+
+	out := reflect.MakeSlice(dst.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		target := out.Index(i)
+		switch {
+		case elem.null:
+			switch target.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+				// left as nil
+			default:
+				return fmt.Errorf("element %d: NULL can't be set into %s", i, target.Type())
+			}
+		case elem.nested:
+			if err := setArrayElems(target, elem.children); err != nil {
+				return err
+			}
+		default:
+			if err := setString(target, elem.value); err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+	}
+	dst.Set(out)
+	return nil
+}
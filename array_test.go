@@ -0,0 +1,182 @@
+package structextract
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPostgresArray_Value(t *testing.T) {
+	tests := []struct {
+		name     string
+		array    PostgresArray
+		expected string
+	}{
+		{"empty", PostgresArray{}, `{}`},
+		{"strings", PostgresArray{"a", "b"}, `{"a","b"}`},
+		{"empty string", PostgresArray{""}, `{""}`},
+		{"quotes", PostgresArray{`say "hi"`}, `{"say \"hi\""}`},
+		{"backslashes", PostgresArray{`C:\dir\`}, `{"C:\\dir\\"}`},
+		{"delimiters", PostgresArray{"a,b", "{c}", " d "}, `{"a,b","{c}"," d "}`},
+		{"null word", PostgresArray{"NULL", nil}, `{"NULL",NULL}`},
+		{"unicode", PostgresArray{"åäö", "日本"}, `{"åäö","日本"}`},
+		{"ints", PostgresArray{int64(1), -2, uint8(3)}, `{1,-2,3}`},
+		{"floats", PostgresArray{1.5, float32(0.25), math.Inf(1), math.Inf(-1), math.NaN()}, `{1.5,0.25,Infinity,-Infinity,NaN}`},
+		{"bools", PostgresArray{true, false}, `{t,f}`},
+		{"nested", PostgresArray{PostgresArray{1, 2}, PostgresArray{3, 4}}, `{{1,2},{3,4}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := test.array.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, v)
+			}
+		})
+	}
+}
+
+func TestParsePostgresArray_RoundTrip(t *testing.T) {
+	values := []string{"", "a", `say "hi"`, `C:\dir\`, "a,b", "{c}", " d ", "NULL", "åäö"}
+	literal, err := PostgresArray{values[0], values[1], values[2], values[3], values[4],
+		values[5], values[6], values[7], values[8]}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []string
+	if err := setPostgresArray(reflect.ValueOf(&out).Elem(), literal); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, values) {
+		t.Fatalf("want %q, got %q", values, out)
+	}
+}
+
+func TestParsePostgresArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		target   interface{}
+		expected interface{}
+	}{
+		{"unquoted", `{a,b , c}`, &[]string{}, &[]string{"a", "b", "c"}},
+		{"ints", `{1,2,3}`, &[]int64{}, &[]int64{1, 2, 3}},
+		{"bools", `{t,f,true}`, &[]bool{}, &[]bool{true, false, true}},
+		{"empty", `{}`, &[]string{"x"}, &[]string{}},
+		{"nulls", `{"a",NULL,null}`, &[]*string{}, &[]*string{strPtr("a"), nil, nil}},
+		{"nested", `{{1,2},{3}}`, &[][]int{}, &[][]int{{1, 2}, {3}}},
+		{"dimensions", `[1:2]={5,6}`, &[]int{}, &[]int{5, 6}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := reflect.ValueOf(test.target).Elem()
+			if err := setPostgresArray(dst, []byte(test.literal)); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.target, test.expected) {
+				t.Fatalf("want %v, got %v", test.expected, test.target)
+			}
+		})
+	}
+}
+
+func TestParsePostgresArray_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal interface{}
+		target  interface{}
+	}{
+		{"not an array", `a,b`, &[]string{}},
+		{"unterminated", `{a,b`, &[]string{}},
+		{"unterminated quote", `{"a}`, &[]string{}},
+		{"trailing", `{a}b`, &[]string{}},
+		{"bad int", `{1,x}`, &[]int{}},
+		{"null into int", `{1,NULL}`, &[]int{}},
+		{"not a slice", `{1}`, new(int)},
+		{"not a string", 12, &[]int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := reflect.ValueOf(test.target).Elem()
+			if err := setPostgresArray(dst, test.literal); err == nil {
+				t.Fatalf("expected an error parsing %v", test.literal)
+			}
+		})
+	}
+}
+
+func TestExtractor_Array(t *testing.T) {
+	type arrayStruct struct {
+		ID     int       `db:"id"`
+		Tags   []string  `db:"tags,array"`
+		Scores []int64   `db:"scores,array"`
+		Matrix [][]int   `db:"matrix,array"`
+		Empty  []string  `db:"empty,array"`
+		Ptr    *[]string `db:"ptr,array"`
+		Names  []*string `db:"names,array"`
+		IDs    []*int64  `db:"ids,array"`
+	}
+	id := int64(9)
+	in := arrayStruct{
+		ID:     1,
+		Tags:   []string{"go", `a "quoted", tag`},
+		Scores: []int64{1, 2},
+		Matrix: [][]int{{1, 2}, {3, 4}},
+		Ptr:    &[]string{"p"},
+		Names:  []*string{strPtr("a"), nil},
+		IDs:    []*int64{&id, nil},
+	}
+
+	m, err := New(&in).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	literals := map[string]interface{}{}
+	for k, v := range m {
+		if valuer, ok := v.(driver.Valuer); ok {
+			v, err = valuer.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		literals[k] = v
+	}
+	exp := map[string]interface{}{
+		"id":     1,
+		"tags":   `{"go","a \"quoted\", tag"}`,
+		"scores": `{1,2}`,
+		"matrix": `{{1,2},{3,4}}`,
+		"empty":  nil,
+		"ptr":    `{"p"}`,
+		"names":  `{"a",NULL}`,
+		"ids":    `{9,NULL}`,
+	}
+	if !reflect.DeepEqual(literals, exp) {
+		t.Fatalf("want %v, got %v", exp, literals)
+	}
+
+	var out arrayStruct
+	if err := New(&out).SetFromTagMap("db", literals); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %+v, got %+v", in, out)
+	}
+}
+
+func TestExtractor_Array_NotSlice(t *testing.T) {
+	type badStruct struct {
+		Name string `db:"name,array"`
+	}
+	if _, err := New(&badStruct{"a"}).ValuesFromTag("db"); err == nil {
+		t.Fatal("expected an error for the array option on a string")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,181 @@
+// Package bind populates structs from the query, headers, path and form of
+// an *http.Request, using the structextract Extractor.
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/iZettle/structextract"
+)
+
+// Tags read by the Binder, one per part of the request
+const (
+	QueryTag  = "query"
+	HeaderTag = "header"
+	PathTag   = "path"
+	FormTag   = "form"
+)
+
+const requiredOption = "required"
+
+// DefaultMaxMemory is the memory used to parse multipart forms, see http.Request.ParseMultipartForm
+const DefaultMaxMemory = 32 << 20
+
+// PathFunc returns the value of a path parameter of the request
+type PathFunc func(r *http.Request, name string) (string, bool)
+
+// FieldError is a field that could not be bound
+type FieldError struct {
+	Source string // Source: the tag of the field, e.g. query
+	Name   string // Name: the tag name, e.g. page
+	Field  string // Field: the field name as defined on the struct
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Source, e.Name, e.Err)
+}
+
+// Unwrap returns the conversion error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors holds every field that could not be bound
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "bind: " + strings.Join(msgs, "; ")
+}
+
+// errMissing is the error of a field with the required tag option that has no value
+var errMissing = errors.New("required value is missing")
+
+// Binder populates structs from requests
+type Binder struct {
+	path      PathFunc
+	maxMemory int64
+}
+
+// New returns a new Binder, path parameters are read with http.Request.PathValue
+func New() *Binder {
+	return &Binder{
+		path:      pathValue,
+		maxMemory: DefaultMaxMemory,
+	}
+}
+
+// WithPath sets the lookup of path parameters, e.g. for a third party router
+func (b *Binder) WithPath(fn PathFunc) *Binder {
+	b.path = fn
+	return b
+}
+
+// WithMaxMemory sets the memory used to parse multipart forms
+func (b *Binder) WithMaxMemory(maxMemory int64) *Binder {
+	b.maxMemory = maxMemory
+	return b
+}
+
+// Bind sets the fields tagged with query, header, path and form from the request,
+// e.g. `query:"page"`, `header:"X-Request-ID"`, `path:"id"` or `form:"email"`.
+// Values are converted to the field types and slices get every value of the key,
+// comma separated header values are split as by Extractor.SetFromHeader.
+// Fields without a value are left untouched, unless they have the required tag option.
+// Every field that could not be bound is reported in the returned Errors,
+// a body that is not a valid form is returned as is
+func (b *Binder) Bind(r *http.Request, ext *structextract.Extractor) error {
+	var errs Errors
+
+	sources := []struct {
+		tag    string
+		lookup func(field structextract.Field) ([]string, error)
+	}{
+		{QueryTag, queryLookup(r)},
+		{HeaderTag, func(field structextract.Field) ([]string, error) { return field.HeaderValues(r.Header), nil }},
+		{PathTag, func(field structextract.Field) ([]string, error) {
+			if v, ok := b.path(r, field.TagName); ok {
+				return []string{v}, nil
+			}
+			return nil, nil
+		}},
+		{FormTag, b.formLookup(r)},
+	}
+
+	for _, source := range sources {
+		fields, err := ext.FieldsFromTag(source.tag)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if field.TagName == "-" {
+				continue
+			}
+			vals, err := source.lookup(field)
+			if err != nil {
+				return err
+			}
+			if len(vals) == 0 {
+				if field.Options.Has(requiredOption) {
+					errs = append(errs, &FieldError{source.tag, field.TagName, field.Name, errMissing})
+				}
+				continue
+			}
+			if err := field.SetStrings(vals); err != nil {
+				errs = append(errs, &FieldError{source.tag, field.TagName, field.Name, err})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Request binds the request with a default Binder, see Binder.Bind
+func Request(r *http.Request, v interface{}) error {
+	return New().Bind(r, structextract.New(v))
+}
+
+func queryLookup(r *http.Request) func(structextract.Field) ([]string, error) {
+	query := r.URL.Query()
+	return func(field structextract.Field) ([]string, error) {
+		return query[field.TagName], nil
+	}
+}
+
+// formLookup parses the body of the request the first time a form value is needed
+func (b *Binder) formLookup(r *http.Request) func(structextract.Field) ([]string, error) {
+	parsed := false
+	return func(field structextract.Field) ([]string, error) {
+		if !parsed {
+			if err := b.parseForm(r); err != nil {
+				return nil, err
+			}
+			parsed = true
+		}
+		return r.PostForm[field.TagName], nil
+	}
+}
+
+func (b *Binder) parseForm(r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		return r.ParseMultipartForm(b.maxMemory)
+	}
+	return r.ParseForm()
+}
+
+func pathValue(r *http.Request, name string) (string, bool) {
+	v := r.PathValue(name)
+	return v, v != ""
+}
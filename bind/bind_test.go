@@ -0,0 +1,138 @@
+package bind
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+type searchRequest struct {
+	ID        int           `path:"id"`
+	Page      int           `query:"page"`
+	Tags      []string      `query:"tag"`
+	Timeout   time.Duration `query:"timeout"`
+	RequestID string        `header:"X-Request-ID,required"`
+	Accept    []string      `header:"Accept"`
+	Email     string        `form:"email"`
+	Ignored   string        `query:"-"`
+	Untagged  string
+}
+
+func TestBind(t *testing.T) {
+	form := url.Values{"email": {"a@b.c"}}
+	r := httptest.NewRequest(http.MethodPost, "/items/42?page=3&tag=a&tag=b&timeout=2s&-=x", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Request-ID", "abc")
+	r.Header.Add("Accept", "text/plain, text/html")
+	r.Header.Add("Accept", "application/json")
+	r.SetPathValue("id", "42")
+
+	var got searchRequest
+	if err := Request(r, &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := searchRequest{
+		ID:        42,
+		Page:      3,
+		Tags:      []string{"a", "b"},
+		Timeout:   2 * time.Second,
+		RequestID: "abc",
+		Accept:    []string{"text/plain", "text/html", "application/json"},
+		Email:     "a@b.c",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestBind_missingValuesLeaveFields(t *testing.T) {
+	v := struct {
+		Page int    `query:"page"`
+		Sort string `query:"sort"`
+	}{Page: 1, Sort: "name"}
+
+	r := httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	if err := Request(r, &v); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v.Page != 2 || v.Sort != "name" {
+		t.Fatalf("want page 2 and sort name, got %d and %s", v.Page, v.Sort)
+	}
+}
+
+func TestBind_errors(t *testing.T) {
+	v := struct {
+		Page  int    `query:"page"`
+		Limit uint   `query:"limit"`
+		Token string `header:"Authorization,required"`
+	}{}
+
+	r := httptest.NewRequest(http.MethodGet, "/?page=first&limit=-1", nil)
+	err := Request(r, &v)
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want Errors, got %v", err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("want 3 errors, got %v", errs)
+	}
+	got := []string{errs[0].Field, errs[1].Field, errs[2].Field}
+	want := []string{"Page", "Limit", "Token"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if errs[2].Source != HeaderTag || errs[2].Err != errMissing {
+		t.Fatalf("want missing header error, got %v", errs[2])
+	}
+}
+
+func TestBinder_WithPath(t *testing.T) {
+	v := struct {
+		Slug string `path:"slug"`
+	}{}
+
+	params := map[string]string{"slug": "hello-world"}
+	b := New().WithPath(func(r *http.Request, name string) (string, bool) {
+		s, ok := params[name]
+		return s, ok
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/posts/hello-world", nil)
+	if err := b.Bind(r, structextract.New(&v)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v.Slug != "hello-world" {
+		t.Fatalf("want %v, got %v", "hello-world", v.Slug)
+	}
+}
+
+func TestBind_multipartForm(t *testing.T) {
+	v := struct {
+		Name  string `form:"name"`
+		Count int    `form:"count"`
+	}{}
+
+	var body strings.Builder
+	w := multipart.NewWriter(&body)
+	w.WriteField("name", "gopher")
+	w.WriteField("count", "7")
+	w.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body.String()))
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	if err := Request(r, &v); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v.Name != "gopher" || v.Count != 7 {
+		t.Fatalf("want gopher and 7, got %s and %d", v.Name, v.Count)
+	}
+}
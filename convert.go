@@ -0,0 +1,111 @@
+package structextract
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setString parses s into dst according to the type of dst,
+// supporting encoding.TextUnmarshaler, strings, bools, numbers,
+// time.Duration and pointers to any of them
+func setString(dst reflect.Value, s string) error {
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	if dst.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := setString(elem.Elem(), s); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(n)
+	default:
+		return fmt.Errorf("cannot parse a string into %s", dst.Type())
+	}
+	return nil
+}
+
+// formatString returns the text of val, the reverse of setString,
+// nil pointers and driver.Valuer values returning nil are reported as not ok
+func formatString(val reflect.Value) (string, bool, error) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return "", false, nil
+		}
+		val = val.Elem()
+	}
+
+	switch v := val.Interface().(type) {
+	case time.Duration:
+		return v.String(), true, nil
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		return string(b), true, err
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil || value == nil {
+			return "", false, err
+		}
+		return formatString(reflect.ValueOf(value))
+	case []byte:
+		return string(v), true, nil
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, val.Type().Bits()), true, nil
+	}
+	return "", false, fmt.Errorf("cannot format %s as a string", val.Type())
+}
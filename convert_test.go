@@ -0,0 +1,97 @@
+package structextract
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   interface{}
+		expected interface{}
+	}{
+		{"string", "hello", new(string), "hello"},
+		{"bool", "true", new(bool), true},
+		{"int", "-42", new(int), -42},
+		{"int8", "8", new(int8), int8(8)},
+		{"uint", "42", new(uint), uint(42)},
+		{"float", "1.5", new(float64), 1.5},
+		{"duration", "1m30s", new(time.Duration), 90 * time.Second},
+		{"time", "2016-10-10T10:00:00Z", new(time.Time), time.Date(2016, 10, 10, 10, 0, 0, 0, time.UTC)},
+		{"pointer", "7", new(*int), intPtr(7)},
+		{"text unmarshaler", "high", new(level), level(1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := reflect.ValueOf(test.target).Elem()
+			if err := setString(dst, test.input); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dst.Interface(), test.expected) {
+				t.Fatalf("want %v, got %v", test.expected, dst.Interface())
+			}
+		})
+	}
+}
+
+func TestSetString_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target interface{}
+	}{
+		{"bool", "maybe", new(bool)},
+		{"int", "1.5", new(int)},
+		{"int8 overflow", "300", new(int8)},
+		{"uint", "-1", new(uint)},
+		{"float", "x", new(float64)},
+		{"duration", "soon", new(time.Duration)},
+		{"unsupported", "a", new(map[string]string)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := setString(reflect.ValueOf(test.target).Elem(), test.input); err == nil {
+				t.Fatalf("expected an error parsing %q", test.input)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestFormatString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+		ok       bool
+	}{
+		{"string", "hello", "hello", true},
+		{"bool", true, "true", true},
+		{"int", -42, "-42", true},
+		{"uint", uint8(42), "42", true},
+		{"float", 1.5, "1.5", true},
+		{"duration", 90 * time.Second, "1m30s", true},
+		{"time", time.Date(2016, 10, 10, 10, 0, 0, 0, time.UTC), "2016-10-10T10:00:00Z", true},
+		{"bytes", []byte("raw"), "raw", true},
+		{"pointer", intPtr(7), "7", true},
+		{"nil pointer", (*int)(nil), "", false},
+		{"text marshaler", level(1), "high", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, ok, err := formatString(reflect.ValueOf(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != test.expected || ok != test.ok {
+				t.Fatalf("want %q %v, got %q %v", test.expected, test.ok, res, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package csvx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+const aliasOption = "alias"
+
+// ErrNotPointer is returned when Decode is not given a pointer to a struct
+// or DecodeAll is not given a pointer to a slice
+var ErrNotPointer = errors.New("csvx: a pointer to a struct or to a slice of structs was expected")
+
+// ParseError locates a cell that could not be converted to the type of its field
+type ParseError struct {
+	Row    int    // Row: 1-based record number, the header is row 1
+	Column int    // Column: 1-based column number
+	Header string // Header: the header of the column
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("csvx: row %d, column %d (%s): %v", e.Row, e.Column, e.Header, e.Err)
+}
+
+// Unwrap returns the conversion error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads CSV records into structs. The first record is the header,
+// each column is mapped to the field whose tag name, or one of its aliases, matches the header.
+// Aliases are set with the alias tag option separated by |, e.g. `csv:"amount,alias=sum|total"`.
+// Columns without a field are skipped and empty cells leave the field untouched.
+// Times are parsed with the layout tag option, RFC 3339 by default
+type Decoder struct {
+	r               *csv.Reader
+	tag             string
+	ignoreCase      bool
+	ignoredFields   []string
+	embeddedStructs bool
+
+	header  []string
+	typ     reflect.Type
+	columns []int // columns: index of the field for each column, -1 when there is none
+	row     int
+}
+
+// NewDecoder returns a new Decoder that reads from r,
+// column names are read from the csv tag
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:   csv.NewReader(r),
+		tag: DefaultTag,
+	}
+}
+
+// WithTag sets the tag that holds the column names
+func (d *Decoder) WithTag(tag string) *Decoder {
+	d.tag = tag
+	return d
+}
+
+// WithComma sets the field delimiter, a comma by default
+func (d *Decoder) WithComma(comma rune) *Decoder {
+	d.r.Comma = comma
+	return d
+}
+
+// IgnoreCase toggles case-insensitive matching of headers
+func (d *Decoder) IgnoreCase(ignore bool) *Decoder {
+	d.ignoreCase = ignore
+	return d
+}
+
+// IgnoreField leaves the given fields untouched, see Extractor.IgnoreField
+func (d *Decoder) IgnoreField(fd ...string) *Decoder {
+	d.ignoredFields = append(d.ignoredFields, fd...)
+	return d
+}
+
+// UseEmbeddedStructs toggles the usage of embedded structs, see Extractor.UseEmbeddedStructs
+func (d *Decoder) UseEmbeddedStructs(use bool) *Decoder {
+	d.embeddedStructs = use
+	return d
+}
+
+// Decode reads the next record into v, a pointer to a struct.
+// io.EOF is returned when there are no more records
+func (d *Decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrNotPointer
+	}
+
+	if d.header == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			return err
+		}
+		d.header = header
+		d.row++
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.row++
+
+	fields, err := d.extractor(v).FieldsFromTag(d.tag)
+	if err != nil {
+		return err
+	}
+	if d.typ == nil {
+		d.typ = rv.Type()
+		d.columns = d.resolve(fields)
+	} else if rv.Type() != d.typ {
+		return fmt.Errorf("csvx: expected %s, got %s", d.typ, rv.Type())
+	}
+
+	for col, cell := range record {
+		if col >= len(d.columns) || d.columns[col] < 0 || cell == "" {
+			continue
+		}
+		if err := setCell(fields[d.columns[col]], cell); err != nil {
+			return &ParseError{Row: d.row, Column: col + 1, Header: d.header[col], Err: err}
+		}
+	}
+
+	return nil
+}
+
+// DecodeAll reads all the remaining records into v, a pointer to a slice of structs
+// or of pointers to structs
+func (d *Decoder) DecodeAll(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrNotPointer
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	for {
+		elem := reflect.New(elemType)
+		err := d.Decode(elem.Interface())
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+}
+
+func (d *Decoder) extractor(v interface{}) *structextract.Extractor {
+	return structextract.New(v).
+		IgnoreField(d.ignoredFields...).
+		UseEmbeddedStructs(d.embeddedStructs)
+}
+
+// resolve returns the index of the field for each column of the header
+func (d *Decoder) resolve(fields []structextract.Field) []int {
+	out := make([]int, len(d.header))
+	for col, header := range d.header {
+		out[col] = -1
+		for i, field := range fields {
+			if field.TagName != "-" && d.matches(header, field) {
+				out[col] = i
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (d *Decoder) matches(header string, field structextract.Field) bool {
+	names := []string{field.TagName}
+	if aliases, ok := field.Options.Get(aliasOption); ok {
+		names = append(names, strings.Split(aliases, "|")...)
+	}
+	header = strings.TrimSpace(header)
+	for _, name := range names {
+		if name == header || (d.ignoreCase && strings.EqualFold(name, header)) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCell converts a cell to the type of the field, times are parsed with the layout tag option
+func setCell(field structextract.Field, cell string) error {
+	typ := field.Value.Type()
+	if typ != timeType && !(typ.Kind() == reflect.Ptr && typ.Elem() == timeType) {
+		return field.SetString(cell)
+	}
+	if !field.Value.CanSet() {
+		return errors.New("cannot be set")
+	}
+
+	layout, ok := field.Options.Get(layoutOption)
+	if !ok {
+		layout = time.RFC3339
+	}
+	t, err := time.Parse(layout, cell)
+	if err != nil {
+		return err
+	}
+	if typ.Kind() == reflect.Ptr {
+		field.Value.Set(reflect.ValueOf(&t))
+	} else {
+		field.Value.Set(reflect.ValueOf(t))
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package csvx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Audit struct {
+	CreatedBy string `csv:"created_by"`
+}
+
+type importRow struct {
+	Audit
+	ID       int        `csv:"id"`
+	Amount   float64    `csv:"amount,alias=sum|total"`
+	Currency *string    `csv:"currency"`
+	Refunded bool       `csv:"refunded"`
+	Date     time.Time  `csv:"date,layout=2006-01-02"`
+	Settled  *time.Time `csv:"settled"`
+	Delay    time.Duration
+	IP       net.IP `csv:"ip"`
+	Internal string `csv:"internal"`
+}
+
+func TestDecoder_DecodeAll(t *testing.T) {
+	in := `ID,Total,currency,refunded,date,settled,ip,unknown,created_by,internal
+1,10.5,SEK,true,2016-10-10,2016-10-11T12:00:00Z,127.0.0.1,x,admin,secret
+2,3,,false,2016-10-12,,,y,,
+`
+	var rows []importRow
+	err := NewDecoder(strings.NewReader(in)).
+		IgnoreCase(true).
+		IgnoreField("Internal").
+		UseEmbeddedStructs(true).
+		DecodeAll(&rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sek := "SEK"
+	settled := time.Date(2016, 10, 11, 12, 0, 0, 0, time.UTC)
+	exp := []importRow{
+		{
+			Audit:    Audit{CreatedBy: "admin"},
+			ID:       1,
+			Amount:   10.5,
+			Currency: &sek,
+			Refunded: true,
+			Date:     time.Date(2016, 10, 10, 0, 0, 0, 0, time.UTC),
+			Settled:  &settled,
+			IP:       net.IPv4(127, 0, 0, 1),
+		},
+		{
+			ID:     2,
+			Amount: 3,
+			Date:   time.Date(2016, 10, 12, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(rows, exp) {
+		t.Fatalf("want %+v, got %+v", exp, rows)
+	}
+}
+
+func TestDecoder_CaseSensitive(t *testing.T) {
+	var rows []*importRow
+	if err := NewDecoder(strings.NewReader("ID,id\n1,2\n")).DecodeAll(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].ID != 2 {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	dec := NewDecoder(strings.NewReader("id;sum\n1;2\n3;4\n")).WithComma(';')
+
+	var row importRow
+	var ids []int
+	for {
+		err := dec.Decode(&row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, row.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 3}) || row.Amount != 4 {
+		t.Fatalf("unexpected result %v %+v", ids, row)
+	}
+}
+
+func TestDecoder_ParseError(t *testing.T) {
+	in := "id,amount,date\n1,2,2016-10-10\n2,abc,2016-10-10\n"
+	var rows []importRow
+	err := NewDecoder(strings.NewReader(in)).DecodeAll(&rows)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Row != 3 || parseErr.Column != 2 || parseErr.Header != "amount" {
+		t.Fatalf("unexpected location %+v", parseErr)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("expected the conversion error to be wrapped, got %v", parseErr.Err)
+	}
+	if !strings.Contains(err.Error(), "row 3, column 2 (amount)") {
+		t.Fatalf("unexpected message %s", err)
+	}
+}
+
+func TestDecoder_BadTime(t *testing.T) {
+	var rows []importRow
+	err := NewDecoder(strings.NewReader("settled\n2016-10-10\n")).DecodeAll(&rows)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Header != "settled" {
+		t.Fatalf("expected a ParseError for settled, got %v", err)
+	}
+}
+
+func TestDecoder_UnexportedTime(t *testing.T) {
+	type row struct {
+		ID      int       `csv:"id"`
+		settled time.Time `csv:"settled"`
+	}
+	var r row
+	err := NewDecoder(strings.NewReader("id,settled\n1,2016-10-10T00:00:00Z\n")).Decode(&r)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Header != "settled" {
+		t.Fatalf("expected a ParseError for settled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cannot be set") {
+		t.Fatalf("unexpected message %s", err)
+	}
+}
+
+func TestDecoder_NotPointer(t *testing.T) {
+	dec := NewDecoder(strings.NewReader("id\n1\n"))
+	if err := dec.Decode(importRow{}); err != ErrNotPointer {
+		t.Fatalf("want %v, got %v", ErrNotPointer, err)
+	}
+	if err := dec.DecodeAll([]importRow{}); err != ErrNotPointer {
+		t.Fatalf("want %v, got %v", ErrNotPointer, err)
+	}
+}
+
+func TestDecoder_RoundTrip(t *testing.T) {
+	type record struct {
+		ID     int       `csv:"id"`
+		Amount float64   `csv:"amount,format=%.2f"`
+		Date   time.Time `csv:"date,layout=2006-01-02"`
+		Note   *string   `csv:"note,omitempty"`
+	}
+	note := "a, \"b\""
+	in := []record{
+		{ID: 1, Amount: 1.25, Date: time.Date(2016, 10, 10, 0, 0, 0, 0, time.UTC), Note: &note},
+		{ID: 2, Amount: 3, Date: time.Date(2016, 10, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAll(in); err != nil {
+		t.Fatal(err)
+	}
+	var out []record
+	if err := NewDecoder(&buf).DecodeAll(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %+v, got %+v", in, out)
+	}
+}
+
+func TestEncoder_Decoder_RoundTrip(t *testing.T) {
+	settled := time.Date(2016, 10, 11, 12, 0, 0, 0, time.UTC)
+	in := []importRow{
+		{
+			Audit:    Audit{CreatedBy: "admin"},
+			ID:       1,
+			Amount:   10.5,
+			Date:     time.Date(2016, 10, 10, 0, 0, 0, 0, time.UTC),
+			Settled:  &settled,
+			IP:       net.IPv4(127, 0, 0, 1),
+			Internal: "secret",
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).IgnoreField("Internal").UseEmbeddedStructs(true).EncodeAll(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "created_by,id,amount,currency,refunded,date,settled,ip\n"
+	if !strings.HasPrefix(buf.String(), header) {
+		t.Fatalf("want header %q, got %q", header, buf.String())
+	}
+
+	var out []importRow
+	err = NewDecoder(&buf).IgnoreField("Internal").UseEmbeddedStructs(true).DecodeAll(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in[0].Internal = ""
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %+v, got %+v", in, out)
+	}
+}
@@ -0,0 +1,255 @@
+// Package csvx encodes structs to CSV and decodes CSV into structs,
+// mapping the columns to the fields by tag with the structextract Extractor.
+package csvx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+// DefaultTag is the tag that holds the column names unless another one is set
+const DefaultTag = "csv"
+
+const (
+	formatOption = "format"
+	layoutOption = "layout"
+)
+
+// ErrNotList is returned when EncodeAll is given something else than a slice or a channel
+var ErrNotList = errors.New("csvx: a slice or a channel of structs was expected")
+
+// Encoder writes structs as CSV records, one column per tagged field.
+// The header is written before the first record, the columns are the tagged fields
+// of the first struct in declaration order and every record has all of them,
+// empty fields with the omitempty tag option are written as empty cells.
+// EncodeAll writes the header from the element type when there are no records.
+// Fields can be formatted with the format tag option, e.g. `csv:"amount,format=%.2f"`,
+// and times with the layout tag option, e.g. `csv:"date,layout=2006-01-02"`
+type Encoder struct {
+	w               *csv.Writer
+	tag             string
+	masker          structextract.Masker
+	ignoredFields   []string
+	embeddedStructs bool
+
+	header []string
+	typ    reflect.Type
+}
+
+// NewEncoder returns a new Encoder that writes to w,
+// column names are read from the csv tag
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:   csv.NewWriter(w),
+		tag: DefaultTag,
+	}
+}
+
+// WithTag sets the tag that holds the column names
+func (enc *Encoder) WithTag(tag string) *Encoder {
+	enc.tag = tag
+	return enc
+}
+
+// Redact masks the cells of sensitive fields with m, see structextract.Extractor.Redact,
+// nil turns masking off
+func (enc *Encoder) Redact(m structextract.Masker) *Encoder {
+	enc.masker = m
+	return enc
+}
+
+// WithComma sets the field delimiter, a comma by default
+func (enc *Encoder) WithComma(comma rune) *Encoder {
+	enc.w.Comma = comma
+	return enc
+}
+
+// IgnoreField leaves the given fields out of the records, see Extractor.IgnoreField
+func (enc *Encoder) IgnoreField(fd ...string) *Encoder {
+	enc.ignoredFields = append(enc.ignoredFields, fd...)
+	return enc
+}
+
+// UseEmbeddedStructs toggles the usage of embedded structs, see Extractor.UseEmbeddedStructs
+func (enc *Encoder) UseEmbeddedStructs(use bool) *Encoder {
+	enc.embeddedStructs = use
+	return enc
+}
+
+// Encode writes a struct, or a pointer to a struct, as a record.
+// Every struct written has to be of the same type as the first one
+func (enc *Encoder) Encode(v interface{}) error {
+	if err := enc.encode(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+// EncodeAll writes every struct of a slice, or received from a channel until it is closed
+func (enc *Encoder) EncodeAll(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.encode(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Chan:
+		for {
+			elem, ok := rv.Recv()
+			if !ok {
+				break
+			}
+			if err := enc.encode(elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrNotList
+	}
+
+	if enc.typ == nil {
+		if err := enc.writeEmptyHeader(rv.Type().Elem()); err != nil {
+			return err
+		}
+	}
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+func (enc *Encoder) encode(rv reflect.Value) error {
+	ptr, err := addressable(rv)
+	if err != nil {
+		return err
+	}
+	if enc.typ != nil && ptr.Type() != enc.typ {
+		return fmt.Errorf("csvx: expected %s, got %s", enc.typ, ptr.Type())
+	}
+
+	fields, err := enc.fields(ptr)
+	if err != nil {
+		return err
+	}
+	if enc.typ == nil {
+		if err := enc.writeHeader(ptr.Type(), fields); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(fields))
+	for i, field := range fields {
+		if field.OmitEmpty() {
+			continue
+		}
+		cell, err := formatCell(field)
+		if err != nil {
+			return fmt.Errorf("csvx: field %s: %v", field.Name, err)
+		}
+		if field.Sensitive && enc.masker != nil && cell != "" {
+			cell = fmt.Sprint(enc.masker(cell))
+		}
+		record[i] = cell
+	}
+	return enc.w.Write(record)
+}
+
+func (enc *Encoder) fields(ptr reflect.Value) ([]structextract.Field, error) {
+	fields, err := structextract.New(ptr.Interface()).
+		IgnoreField(enc.ignoredFields...).
+		UseEmbeddedStructs(enc.embeddedStructs).
+		FieldsFromTag(enc.tag)
+	if err != nil {
+		return nil, err
+	}
+	return columns(fields), nil
+}
+
+func (enc *Encoder) writeHeader(typ reflect.Type, fields []structextract.Field) error {
+	enc.typ = typ
+	for _, field := range fields {
+		enc.header = append(enc.header, field.TagName)
+	}
+	return enc.w.Write(enc.header)
+}
+
+// writeEmptyHeader writes the header of a list without records from the type of its elements,
+// nothing is written when the elements are not structs or pointers to structs, e.g. interface{}
+func (enc *Encoder) writeEmptyHeader(elem reflect.Type) error {
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil
+	}
+	ptr := reflect.New(elem)
+	fields, err := enc.fields(ptr)
+	if err != nil {
+		return err
+	}
+	return enc.writeHeader(ptr.Type(), fields)
+}
+
+// addressable returns a pointer to the struct held by rv, copying it when needed
+func addressable(rv reflect.Value) (reflect.Value, error) {
+	for rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	switch {
+	case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct:
+		return rv, nil
+	case rv.Kind() == reflect.Struct && rv.CanAddr():
+		return rv.Addr(), nil
+	case rv.Kind() == reflect.Struct:
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		return ptr, nil
+	}
+	return reflect.Value{}, errors.New("csvx: a struct or a pointer to struct was expected")
+}
+
+// columns leaves out the fields tagged with -
+func columns(fields []structextract.Field) []structextract.Field {
+	out := fields[:0]
+	for _, field := range fields {
+		if field.TagName != "-" {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatCell returns the text of a cell, nil pointers and NULL values are empty.
+// The format and layout tag options apply, any other value is formatted as by Field.FormatString
+func formatCell(field structextract.Field) (string, error) {
+	val := field.Value
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+
+	if format, ok := field.Options.Get(formatOption); ok {
+		return fmt.Sprintf(format, val.Interface()), nil
+	}
+	if val.Type() == timeType {
+		layout, ok := field.Options.Get(layoutOption)
+		if !ok {
+			layout = time.RFC3339
+		}
+		return val.Interface().(time.Time).Format(layout), nil
+	}
+
+	text, _, err := field.FormatString()
+	return text, err
+}
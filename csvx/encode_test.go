@@ -0,0 +1,166 @@
+package csvx
+
+import (
+	"bytes"
+	"database/sql"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+type payment struct {
+	ID       int            `csv:"id" json:"paymentId"`
+	Amount   float64        `csv:"amount,format=%.2f"`
+	Currency string         `csv:"currency"`
+	Note     string         `csv:"note,omitempty"`
+	Date     time.Time      `csv:"date,layout=2006-01-02"`
+	Settled  *time.Time     `csv:"settled"`
+	Refunded bool           `csv:"refunded"`
+	Delay    time.Duration  `csv:"delay"`
+	IP       net.IP         `csv:"ip"`
+	Ref      sql.NullString `csv:"ref"`
+	Secret   string         `csv:"-"`
+	Internal string
+}
+
+func fakePayments() []payment {
+	settled := time.Date(2016, 10, 11, 12, 0, 0, 0, time.UTC)
+	return []payment{
+		{
+			ID:       1,
+			Amount:   10,
+			Currency: "SEK",
+			Note:     `with "quotes", and commas`,
+			Date:     time.Date(2016, 10, 10, 0, 0, 0, 0, time.UTC),
+			Settled:  &settled,
+			Delay:    90 * time.Second,
+			IP:       net.IPv4(127, 0, 0, 1),
+			Ref:      sql.NullString{String: "abc", Valid: true},
+			Secret:   "secret",
+		},
+		{
+			ID:       2,
+			Amount:   5.555,
+			Currency: "EUR",
+			Date:     time.Date(2016, 10, 12, 0, 0, 0, 0, time.UTC),
+			Refunded: true,
+		},
+	}
+}
+
+const expectedPayments = `id,amount,currency,note,date,settled,refunded,delay,ip,ref
+1,10.00,SEK,"with ""quotes"", and commas",2016-10-10,2016-10-11T12:00:00Z,false,1m30s,127.0.0.1,abc
+2,5.55,EUR,,2016-10-12,,true,0s,,
+`
+
+func TestEncoder_EncodeAll_Slice(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAll(fakePayments()); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expectedPayments {
+		t.Fatalf("want\n%s\ngot\n%s", expectedPayments, buf.String())
+	}
+}
+
+func TestEncoder_EncodeAll_Channel(t *testing.T) {
+	ch := make(chan *payment)
+	go func() {
+		for _, p := range fakePayments() {
+			p := p
+			ch <- &p
+		}
+		close(ch)
+	}()
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAll(ch); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expectedPayments {
+		t.Fatalf("want\n%s\ngot\n%s", expectedPayments, buf.String())
+	}
+}
+
+func TestEncoder_EncodeAll_Empty(t *testing.T) {
+	header := strings.SplitN(expectedPayments, "\n", 2)[0] + "\n"
+
+	ch := make(chan *payment)
+	close(ch)
+	for _, v := range []interface{}{[]payment{}, ch} {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf).EncodeAll(v); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != header {
+			t.Fatalf("want\n%s\ngot\n%s", header, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAll([]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("want no output, got\n%s", buf.String())
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf).WithTag("json").WithComma(';')
+	for _, p := range fakePayments() {
+		if err := enc.Encode(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exp := "paymentId\n1\n2\n"
+	if buf.String() != exp {
+		t.Fatalf("want %q, got %q", exp, buf.String())
+	}
+}
+
+func TestEncoder_Redact(t *testing.T) {
+	type card struct {
+		ID     int    `csv:"id"`
+		Number string `csv:"number,redact"`
+		Holder string `csv:"holder" sensitive:"true"`
+		Note   string `csv:"note,secret"`
+	}
+	cards := []card{{ID: 1, Number: "4111111111111234", Holder: "Gopher"}}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Redact(structextract.MaskLast(4)).EncodeAll(cards); err != nil {
+		t.Fatal(err)
+	}
+	exp := "id,number,holder,note\n1,****1234,****pher,\n"
+	if buf.String() != exp {
+		t.Fatalf("want %q, got %q", exp, buf.String())
+	}
+}
+
+func TestEncoder_MixedTypes(t *testing.T) {
+	type other struct {
+		ID int `csv:"id"`
+	}
+	enc := NewEncoder(&bytes.Buffer{})
+	if err := enc.Encode(payment{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(other{}); err == nil {
+		t.Fatal("expected an error encoding a different type")
+	}
+}
+
+func TestEncoder_Errors(t *testing.T) {
+	if err := NewEncoder(&bytes.Buffer{}).EncodeAll(payment{}); err != ErrNotList {
+		t.Fatalf("want %v, got %v", ErrNotList, err)
+	}
+	err := NewEncoder(&bytes.Buffer{}).EncodeAll([]string{"a"})
+	if err == nil || !strings.Contains(err.Error(), "struct") {
+		t.Fatalf("expected a struct error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package ddl generates CREATE TABLE statements from the tagged fields of a struct,
+// using the structextract Extractor.
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iZettle/structextract"
+	"github.com/iZettle/structextract/sqlgen"
+)
+
+const (
+	typeOption    = "type"
+	defaultOption = "default"
+)
+
+// Column describes a table column derived from a struct field
+type Column struct {
+	Name       string // Name: the column name, from the tag
+	Type       string // Type: the column type, from the Go type or the type tag option
+	NotNull    bool   // NotNull: false for pointers and database/sql Null types
+	PrimaryKey bool   // PrimaryKey: the field has the pk tag option
+	Unique     bool   // Unique: the field has the unique tag option
+	Default    string // Default: the SQL expression of the default tag option, if any
+}
+
+// Generator generates DDL statements for a dialect
+type Generator struct {
+	dialect *sqlgen.Dialect
+	tag     string
+}
+
+// New returns a new Generator for the given dialect,
+// column names are read from the db tag
+func New(d *sqlgen.Dialect) *Generator {
+	return &Generator{
+		dialect: d,
+		tag:     sqlgen.DefaultTag,
+	}
+}
+
+// WithTag sets the tag that holds the column names
+func (g *Generator) WithTag(tag string) *Generator {
+	g.tag = tag
+	return g
+}
+
+// Columns returns a Column for every tagged field, in declaration order.
+// Embedded structs are included when the Extractor uses them, see UseEmbeddedStructs.
+// The column type can be overridden with the type tag option, e.g. `db:"meta,type=jsonb"`
+func (g *Generator) Columns(ext *structextract.Extractor) ([]Column, error) {
+	types, ok := typeMaps[g.dialect]
+	if !ok {
+		return nil, fmt.Errorf("ddl: dialect %s is not supported", g.dialect)
+	}
+
+	fields, err := ext.FieldsFromTag(g.tag)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		if field.TagName == "-" {
+			continue
+		}
+		typ, nullable, ok := types.columnType(field.Value.Type())
+		override, set, err := optionValue(field.Options, typeOption)
+		if err != nil {
+			return nil, fmt.Errorf("ddl: field %s: %v", field.Name, err)
+		}
+		if set {
+			typ, ok = override, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("ddl: field %s: no column type for %s, use the type tag option",
+				field.Name, field.Value.Type())
+		}
+
+		def, _, err := optionValue(field.Options, defaultOption)
+		if err != nil {
+			return nil, fmt.Errorf("ddl: field %s: %v", field.Name, err)
+		}
+		out = append(out, Column{
+			Name:       field.TagName,
+			Type:       typ,
+			NotNull:    !nullable,
+			PrimaryKey: field.Options.Has(structextract.PrimaryKeyOption),
+			Unique:     field.Options.Has(structextract.UniqueOption),
+			Default:    def,
+		})
+	}
+
+	return out, nil
+}
+
+// optionValue returns the value of a key=value tag option that may contain commas,
+// e.g. type=numeric(10,2) or default='a,b'. The options following the key are joined back
+// until the parentheses and quotes of the value are balanced
+func optionValue(options structextract.TagOptions, key string) (string, bool, error) {
+	for i, option := range options {
+		if !strings.HasPrefix(option, key+"=") {
+			continue
+		}
+		value := option[len(key)+1:]
+		for j := i + 1; !balanced(value); j++ {
+			if j == len(options) {
+				return "", false, fmt.Errorf("unbalanced parentheses or quotes in %s option %q", key, value)
+			}
+			value += "," + options[j]
+		}
+		return value, true, nil
+	}
+	return "", false, nil
+}
+
+// balanced reports whether every parenthesis of s is closed and every quote terminated,
+// parentheses inside quotes are ignored
+func balanced(s string) bool {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+// CreateTable returns a CREATE TABLE statement for the tagged fields, see Columns
+// e.g.
+//
+//	CREATE TABLE "users" (
+//		"id" BIGINT NOT NULL,
+//		"name" TEXT NOT NULL,
+//		PRIMARY KEY ("id")
+//	)
+func (g *Generator) CreateTable(table string, ext *structextract.Extractor) (string, error) {
+	columns, err := g.Columns(ext)
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", sqlgen.ErrNoColumns
+	}
+
+	var defs, keys []string
+	for _, col := range columns {
+		def := g.dialect.Quote(col.Name) + " " + col.Type
+		if col.NotNull {
+			def += " NOT NULL"
+		}
+		if col.Default != "" {
+			def += " DEFAULT " + col.Default
+		}
+		if col.Unique {
+			def += " UNIQUE"
+		}
+		defs = append(defs, def)
+		if col.PrimaryKey {
+			keys = append(keys, g.dialect.Quote(col.Name))
+		}
+	}
+	if len(keys) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+
+	return "CREATE TABLE " + g.dialect.Quote(table) + " (\n\t" + strings.Join(defs, ",\n\t") + "\n)", nil
+}
@@ -0,0 +1,203 @@
+package ddl
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iZettle/structextract"
+	"github.com/iZettle/structextract/sqlgen"
+)
+
+type timestamps struct {
+	CreatedAt time.Time  `db:"created_at,readonly,default=CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type user struct {
+	timestamps
+	ID       int64             `db:"id,pk"`
+	Email    string            `db:"email,unique"`
+	Nickname sql.NullString    `db:"nickname"`
+	Age      *int32            `db:"age"`
+	Score    float64           `db:"score"`
+	Avatar   []byte            `db:"avatar"`
+	Meta     map[string]string `db:"meta,type=JSONB"`
+	Active   bool              `db:"active,default=true"`
+	Cache    string
+}
+
+func fakeUser() *structextract.Extractor {
+	return structextract.New(&user{}).UseEmbeddedStructs(true)
+}
+
+func TestGenerator_CreateTable(t *testing.T) {
+	tests := []struct {
+		dialect  *sqlgen.Dialect
+		expected string
+	}{
+		{
+			sqlgen.Postgres,
+			`CREATE TABLE "users" (
+	"created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"deleted_at" TIMESTAMPTZ,
+	"id" BIGINT NOT NULL,
+	"email" TEXT NOT NULL UNIQUE,
+	"nickname" TEXT,
+	"age" INTEGER,
+	"score" DOUBLE PRECISION NOT NULL,
+	"avatar" BYTEA NOT NULL,
+	"meta" JSONB NOT NULL,
+	"active" BOOLEAN NOT NULL DEFAULT true,
+	PRIMARY KEY ("id")
+)`,
+		},
+		{
+			sqlgen.MySQL,
+			"CREATE TABLE `users` (\n" +
+				"\t`created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+				"\t`deleted_at` DATETIME(6),\n" +
+				"\t`id` BIGINT NOT NULL,\n" +
+				"\t`email` VARCHAR(255) NOT NULL UNIQUE,\n" +
+				"\t`nickname` VARCHAR(255),\n" +
+				"\t`age` INT,\n" +
+				"\t`score` DOUBLE NOT NULL,\n" +
+				"\t`avatar` BLOB NOT NULL,\n" +
+				"\t`meta` JSONB NOT NULL,\n" +
+				"\t`active` BOOLEAN NOT NULL DEFAULT true,\n" +
+				"\tPRIMARY KEY (`id`)\n" +
+				")",
+		},
+		{
+			sqlgen.SQLite,
+			`CREATE TABLE "users" (
+	"created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	"deleted_at" DATETIME,
+	"id" INTEGER NOT NULL,
+	"email" TEXT NOT NULL UNIQUE,
+	"nickname" TEXT,
+	"age" INTEGER,
+	"score" REAL NOT NULL,
+	"avatar" BLOB NOT NULL,
+	"meta" JSONB NOT NULL,
+	"active" BOOLEAN NOT NULL DEFAULT true,
+	PRIMARY KEY ("id")
+)`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			res, err := New(test.dialect).CreateTable("users", fakeUser())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != test.expected {
+				t.Fatalf("want\n%s\ngot\n%s", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestGenerator_CreateTable_CompositeKey(t *testing.T) {
+	type membership struct {
+		TenantID int    `db:"tenant_id,pk"`
+		UserID   int    `db:"user_id,pk"`
+		Role     string `db:"role"`
+	}
+	res, err := New(sqlgen.Postgres).CreateTable("memberships", structextract.New(&membership{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(res, "PRIMARY KEY (\"tenant_id\", \"user_id\")\n)") {
+		t.Fatalf("unexpected statement\n%s", res)
+	}
+}
+
+func TestGenerator_Columns_EmbeddedStructsOff(t *testing.T) {
+	cols, err := New(sqlgen.Postgres).Columns(structextract.New(&user{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols[0].Name != "id" {
+		t.Fatalf("embedded columns should be left out, got %v", cols[0])
+	}
+	exp := Column{Name: "id", Type: "BIGINT", NotNull: true, PrimaryKey: true}
+	if !reflect.DeepEqual(cols[0], exp) {
+		t.Fatalf("want %+v, got %+v", exp, cols[0])
+	}
+}
+
+func TestGenerator_Columns_OptionsWithCommas(t *testing.T) {
+	type invoice struct {
+		Amount float64 `db:"amount,type=numeric(10,2),unique"`
+		Status string  `db:"status,default='a,b'"`
+		Mood   string  `db:"mood,type=\"Mood\""`
+	}
+	cols, err := New(sqlgen.Postgres).Columns(structextract.New(&invoice{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Column{
+		{Name: "amount", Type: "numeric(10,2)", NotNull: true, Unique: true},
+		{Name: "status", Type: "TEXT", NotNull: true, Default: "'a,b'"},
+		{Name: "mood", Type: `"Mood"`, NotNull: true},
+	}
+	if !reflect.DeepEqual(cols, exp) {
+		t.Fatalf("want %+v, got %+v", exp, cols)
+	}
+}
+
+func TestGenerator_Columns_UnbalancedOption(t *testing.T) {
+	tests := []interface{}{
+		&struct {
+			Amount float64 `db:"amount,type=numeric(10"`
+		}{},
+		&struct {
+			Status string `db:"status,default='a"`
+		}{},
+	}
+	for _, test := range tests {
+		if _, err := New(sqlgen.Postgres).Columns(structextract.New(test)); err == nil {
+			t.Fatalf("want error for an unbalanced option in %T", test)
+		}
+	}
+}
+
+func TestGenerator_Columns_Ignored(t *testing.T) {
+	type session struct {
+		ID    int64  `db:"id,pk"`
+		Token string `db:"-"`
+	}
+	cols, err := New(sqlgen.Postgres).Columns(structextract.New(&session{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != 1 || cols[0].Name != "id" {
+		t.Fatalf("want only the id column, got %+v", cols)
+	}
+}
+
+func TestGenerator_UnknownType(t *testing.T) {
+	type bad struct {
+		Tags []string `db:"tags"`
+	}
+	_, err := New(sqlgen.Postgres).CreateTable("bad", structextract.New(&bad{}))
+	if err == nil || !strings.Contains(err.Error(), "Tags") {
+		t.Fatalf("expected an error for field Tags, got %v", err)
+	}
+}
+
+func TestGenerator_UnsupportedDialect(t *testing.T) {
+	if _, err := New(sqlgen.SQLServer).CreateTable("users", fakeUser()); err == nil {
+		t.Fatal("sqlserver should not be supported")
+	}
+}
+
+func TestGenerator_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if _, err := New(sqlgen.Postgres).CreateTable("users", structextract.New(&test)); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
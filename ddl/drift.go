@@ -0,0 +1,274 @@
+package ddl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/iZettle/structextract"
+)
+
+// TableColumn describes a column of an existing table
+type TableColumn struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// DriftKind is the kind of difference found between a struct and a table
+type DriftKind int
+
+const (
+	// MissingColumn is a tagged field without a column in the table
+	MissingColumn DriftKind = iota
+	// ExtraColumn is a column in the table without a tagged field
+	ExtraColumn
+	// TypeMismatch is a column whose type differs from the one of the field
+	TypeMismatch
+	// NullabilityMismatch is a column that is nullable when the field is not, or the other way around
+	NullabilityMismatch
+)
+
+func (k DriftKind) String() string {
+	switch k {
+	case MissingColumn:
+		return "missing column"
+	case ExtraColumn:
+		return "extra column"
+	case TypeMismatch:
+		return "type mismatch"
+	case NullabilityMismatch:
+		return "nullability mismatch"
+	}
+	return "unknown drift"
+}
+
+// Drift is a difference between a struct and a table
+type Drift struct {
+	Kind     DriftKind
+	Column   string
+	Expected string // Expected: type or nullability derived from the struct
+	Actual   string // Actual: type or nullability found in the table
+}
+
+func (d Drift) String() string {
+	if d.Kind == MissingColumn || d.Kind == ExtraColumn {
+		return fmt.Sprintf("%s %s", d.Kind, d.Column)
+	}
+	return fmt.Sprintf("%s %s: expected %s, got %s", d.Kind, d.Column, d.Expected, d.Actual)
+}
+
+// Diff compares the columns derived from the tagged fields, see Columns,
+// with the columns of a table and returns the differences: missing columns and
+// mismatches in struct order, then extra columns in table order.
+// Types are compared after normalizing common aliases, e.g. int8 and BIGINT,
+// length and precision are only compared when both sides have them.
+// The MySQL information_schema data_type spellings tinyint for BOOLEAN
+// and bigint for BIGINT UNSIGNED are accepted as well.
+func (g *Generator) Diff(ext *structextract.Extractor, table []TableColumn) ([]Drift, error) {
+	columns, err := g.Columns(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := make(map[string]TableColumn, len(table))
+	for _, col := range table {
+		actual[col.Name] = col
+	}
+
+	var out []Drift
+	expected := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		expected[col.Name] = true
+		found, ok := actual[col.Name]
+		if !ok {
+			out = append(out, Drift{Kind: MissingColumn, Column: col.Name, Expected: col.Type})
+			continue
+		}
+		if !sameType(col.Type, found.Type) {
+			out = append(out, Drift{Kind: TypeMismatch, Column: col.Name, Expected: col.Type, Actual: found.Type})
+		}
+		if col.NotNull == found.Nullable {
+			out = append(out, Drift{
+				Kind:     NullabilityMismatch,
+				Column:   col.Name,
+				Expected: nullability(!col.NotNull),
+				Actual:   nullability(found.Nullable),
+			})
+		}
+	}
+	for _, col := range table {
+		if !expected[col.Name] {
+			out = append(out, Drift{Kind: ExtraColumn, Column: col.Name, Actual: col.Type})
+		}
+	}
+
+	return out, nil
+}
+
+// FromInformationSchema returns a TableColumn from the column_name, data_type
+// and is_nullable values of an information_schema.columns row
+func FromInformationSchema(name, dataType, isNullable string) TableColumn {
+	return TableColumn{
+		Name:     name,
+		Type:     dataType,
+		Nullable: strings.EqualFold(strings.TrimSpace(isNullable), "YES"),
+	}
+}
+
+// definitionKeywords end the type of a column definition
+var definitionKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "CHECK": true, "CONSTRAINT": true, "COLLATE": true,
+	"GENERATED": true, "AUTO_INCREMENT": true, "AUTOINCREMENT": true,
+}
+
+// tableConstraint matches the start of a table constraint instead of a column definition.
+// A keyword alone is not enough, columns can be named key, index or check
+var tableConstraint = regexp.MustCompile(`(?i)^(` +
+	`(PRIMARY|FOREIGN)\s+KEY\b|` +
+	`UNIQUE\s*(\(|KEY\b|INDEX\b)|` +
+	`(CHECK|EXCLUDE)\s*\(|EXCLUDE\s+USING\b|` +
+	`CONSTRAINT\s+\S+\s+(PRIMARY|UNIQUE|FOREIGN|CHECK|EXCLUDE)\b|` +
+	`(FULLTEXT|SPATIAL)\s+(KEY|INDEX)\b|` +
+	`(KEY|INDEX)\s*(\(|\S+\s+(\(|USING\b))` +
+	`)`)
+
+// ParseCreateTable returns the columns of a CREATE TABLE statement.
+// Table constraints are skipped, a column is nullable unless it is NOT NULL or PRIMARY KEY
+func ParseCreateTable(stmt string) ([]TableColumn, error) {
+	start := strings.Index(stmt, "(")
+	end := strings.LastIndex(stmt, ")")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("ddl: no column definitions found in %q", stmt)
+	}
+
+	var out []TableColumn
+	for _, def := range splitTopLevel(stmt[start+1 : end]) {
+		words := strings.Fields(def)
+		if len(words) == 0 {
+			continue
+		}
+		if tableConstraint.MatchString(strings.TrimSpace(def)) {
+			continue
+		}
+		if len(words) < 2 {
+			return nil, fmt.Errorf("ddl: no type found in column definition %q", def)
+		}
+
+		var typ []string
+		rest := words[1:]
+		for len(rest) > 0 && !definitionKeywords[strings.ToUpper(rest[0])] {
+			typ = append(typ, rest[0])
+			rest = rest[1:]
+		}
+		constraints := strings.ToUpper(strings.Join(rest, " "))
+
+		out = append(out, TableColumn{
+			Name: unquote(words[0]),
+			Type: strings.Join(typ, " "),
+			Nullable: !strings.Contains(constraints, "NOT NULL") &&
+				!strings.Contains(constraints, "PRIMARY KEY"),
+		})
+	}
+
+	return out, nil
+}
+
+// splitTopLevel splits on the commas that are not inside parentheses or quotes
+func splitTopLevel(s string) (out []string) {
+	depth := 0
+	var quote rune
+	last := 0
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(out, s[last:])
+}
+
+func unquote(ident string) string {
+	if len(ident) >= 2 {
+		switch ident[0] {
+		case '"', '`':
+			return ident[1 : len(ident)-1]
+		case '[':
+			return strings.TrimSuffix(ident[1:], "]")
+		}
+	}
+	return ident
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// typeAliases maps the spellings used by information_schema and DDL to a single name
+var typeAliases = map[string]string{
+	"INT":                         "INTEGER",
+	"INT2":                        "SMALLINT",
+	"INT4":                        "INTEGER",
+	"INT8":                        "BIGINT",
+	"FLOAT4":                      "REAL",
+	"FLOAT8":                      "DOUBLE PRECISION",
+	"DOUBLE":                      "DOUBLE PRECISION",
+	"BOOL":                        "BOOLEAN",
+	"TINYINT(1)":                  "BOOLEAN",
+	"DECIMAL":                     "NUMERIC",
+	"CHARACTER VARYING":           "VARCHAR",
+	"CHARACTER":                   "CHAR",
+	"TIMESTAMP WITH TIME ZONE":    "TIMESTAMPTZ",
+	"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+}
+
+// sameType compares the expected type of a field with the actual type of a column,
+// ignoring the length or precision when only one of them has it.
+// MySQL information_schema data_type reports BOOLEAN as tinyint and leaves out UNSIGNED,
+// so the expected type is reduced the same way when the actual type lacks them
+func sameType(expected, actual string) bool {
+	if !strings.Contains(strings.ToUpper(actual), "UNSIGNED") {
+		expected = strings.TrimSpace(strings.TrimSuffix(strings.ToUpper(expected), "UNSIGNED"))
+	}
+	aBase, aArgs := normalizeType(expected)
+	bBase, bArgs := normalizeType(actual)
+	if aBase == "BOOLEAN" && bBase == "TINYINT" && bArgs == "" {
+		return true
+	}
+	if aBase != bBase {
+		return false
+	}
+	return aArgs == "" || bArgs == "" || aArgs == bArgs
+}
+
+func normalizeType(t string) (base, args string) {
+	t = strings.ToUpper(strings.Join(strings.Fields(t), " "))
+	if alias, ok := typeAliases[t]; ok {
+		return alias, ""
+	}
+
+	base = t
+	if i := strings.Index(t, "("); i >= 0 {
+		base = strings.TrimSpace(t[:i])
+		args = strings.Replace(t[i:], " ", "", -1)
+	}
+	if alias, ok := typeAliases[base]; ok {
+		base = alias
+	}
+	return base, args
+}
@@ -0,0 +1,179 @@
+package ddl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iZettle/structextract"
+	"github.com/iZettle/structextract/sqlgen"
+)
+
+type post struct {
+	ID        int64   `db:"id,pk"`
+	Title     string  `db:"title"`
+	Body      *string `db:"body"`
+	Views     int32   `db:"views"`
+	Published bool    `db:"published"`
+}
+
+func TestGenerator_Diff(t *testing.T) {
+	table := []TableColumn{
+		FromInformationSchema("id", "int8", "NO"),
+		FromInformationSchema("title", "character varying", "NO"),
+		FromInformationSchema("body", "text", "NO"),
+		FromInformationSchema("published", "bool", "NO"),
+		FromInformationSchema("legacy", "text", "YES"),
+	}
+
+	res, err := New(sqlgen.Postgres).Diff(structextract.New(&post{}), table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Drift{
+		{Kind: TypeMismatch, Column: "title", Expected: "TEXT", Actual: "character varying"},
+		{Kind: NullabilityMismatch, Column: "body", Expected: "NULL", Actual: "NOT NULL"},
+		{Kind: MissingColumn, Column: "views", Expected: "INTEGER"},
+		{Kind: ExtraColumn, Column: "legacy", Actual: "text"},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestGenerator_Diff_NoDrift(t *testing.T) {
+	ext := structextract.New(&post{})
+	g := New(sqlgen.MySQL)
+	stmt, err := g.CreateTable("posts", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ParseCreateTable(stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := g.Diff(ext, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("no drift was expected, got %v", res)
+	}
+}
+
+func TestGenerator_Diff_MySQLInformationSchema(t *testing.T) {
+	type counter struct {
+		ID     uint64 `db:"id,pk"`
+		Hits   uint32 `db:"hits"`
+		Active bool   `db:"active"`
+	}
+	table := []TableColumn{
+		FromInformationSchema("id", "bigint", "NO"),
+		FromInformationSchema("hits", "int", "NO"),
+		FromInformationSchema("active", "tinyint", "NO"),
+	}
+
+	res, err := New(sqlgen.MySQL).Diff(structextract.New(&counter{}), table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("no drift was expected, got %v", res)
+	}
+}
+
+func TestParseCreateTable(t *testing.T) {
+	stmt := `CREATE TABLE IF NOT EXISTS "posts" (
+		"id" BIGSERIAL PRIMARY KEY,
+		title VARCHAR(200) NOT NULL DEFAULT 'a, b',
+		price NUMERIC(10, 2),
+		"created_at" timestamp with time zone NOT NULL DEFAULT now(),
+		CONSTRAINT posts_title_key UNIQUE (title),
+		FOREIGN KEY (author_id) REFERENCES authors (id)
+	)`
+
+	res, err := ParseCreateTable(stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []TableColumn{
+		{Name: "id", Type: "BIGSERIAL", Nullable: false},
+		{Name: "title", Type: "VARCHAR(200)", Nullable: false},
+		{Name: "price", Type: "NUMERIC(10, 2)", Nullable: true},
+		{Name: "created_at", Type: "timestamp with time zone", Nullable: false},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestParseCreateTable_KeywordColumns(t *testing.T) {
+	stmt := `CREATE TABLE kv (
+		key TEXT NOT NULL,
+		index INT NOT NULL,
+		check BOOLEAN,
+		unique TEXT,
+		PRIMARY KEY (key),
+		UNIQUE (index),
+		CHECK (index > 0),
+		CONSTRAINT kv_unique UNIQUE (unique),
+		KEY kv_index (index)
+	)`
+
+	res, err := ParseCreateTable(stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []TableColumn{
+		{Name: "key", Type: "TEXT", Nullable: false},
+		{Name: "index", Type: "INT", Nullable: false},
+		{Name: "check", Type: "BOOLEAN", Nullable: true},
+		{Name: "unique", Type: "TEXT", Nullable: true},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestParseCreateTable_Invalid(t *testing.T) {
+	if _, err := ParseCreateTable("CREATE TABLE posts"); err == nil {
+		t.Fatal("expected an error without column definitions")
+	}
+}
+
+func TestSameType(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"BIGINT", "int8", true},
+		{"TIMESTAMPTZ", "timestamp with time zone", true},
+		{"DATETIME(6)", "datetime", true},
+		{"NUMERIC(10,2)", "numeric(10, 2)", true},
+		{"NUMERIC(10,2)", "numeric(12,2)", false},
+		{"INT", "INTEGER", true},
+		{"BOOLEAN", "tinyint(1)", true},
+		{"TEXT", "VARCHAR(255)", false},
+		{"BOOLEAN", "tinyint", true},
+		{"BOOLEAN", "tinyint(4)", false},
+		{"BIGINT UNSIGNED", "bigint", true},
+		{"INT UNSIGNED", "int unsigned", true},
+		{"BIGINT", "bigint unsigned", false},
+	}
+	for _, test := range tests {
+		if got := sameType(test.a, test.b); got != test.expected {
+			t.Errorf("sameType(%q, %q): want %v, got %v", test.a, test.b, test.expected, got)
+		}
+	}
+}
+
+func TestDrift_String(t *testing.T) {
+	d := Drift{Kind: TypeMismatch, Column: "title", Expected: "TEXT", Actual: "varchar"}
+	if got := d.String(); got != "type mismatch title: expected TEXT, got varchar" {
+		t.Fatalf("unexpected string %q", got)
+	}
+	d = Drift{Kind: MissingColumn, Column: "views"}
+	if got := d.String(); got != "missing column views" {
+		t.Fatalf("unexpected string %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package ddl
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/iZettle/structextract/sqlgen"
+)
+
+// typeMap maps Go types to the column type of a dialect
+type typeMap struct {
+	kinds map[reflect.Kind]string
+	types map[reflect.Type]string
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// nullTypes maps the database/sql Null types to the Go type they wrap
+var nullTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+	reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+	reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+	reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+	reflect.TypeOf(sql.NullTime{}):    timeType,
+}
+
+var typeMaps = map[*sqlgen.Dialect]typeMap{
+	sqlgen.Postgres: {
+		kinds: map[reflect.Kind]string{
+			reflect.Bool:    "BOOLEAN",
+			reflect.Int:     "BIGINT",
+			reflect.Int8:    "SMALLINT",
+			reflect.Int16:   "SMALLINT",
+			reflect.Int32:   "INTEGER",
+			reflect.Int64:   "BIGINT",
+			reflect.Uint:    "BIGINT",
+			reflect.Uint8:   "SMALLINT",
+			reflect.Uint16:  "INTEGER",
+			reflect.Uint32:  "BIGINT",
+			reflect.Uint64:  "NUMERIC(20)",
+			reflect.Float32: "REAL",
+			reflect.Float64: "DOUBLE PRECISION",
+			reflect.String:  "TEXT",
+		},
+		types: map[reflect.Type]string{
+			timeType:  "TIMESTAMPTZ",
+			bytesType: "BYTEA",
+		},
+	},
+	sqlgen.MySQL: {
+		kinds: map[reflect.Kind]string{
+			reflect.Bool:    "BOOLEAN",
+			reflect.Int:     "BIGINT",
+			reflect.Int8:    "TINYINT",
+			reflect.Int16:   "SMALLINT",
+			reflect.Int32:   "INT",
+			reflect.Int64:   "BIGINT",
+			reflect.Uint:    "BIGINT UNSIGNED",
+			reflect.Uint8:   "TINYINT UNSIGNED",
+			reflect.Uint16:  "SMALLINT UNSIGNED",
+			reflect.Uint32:  "INT UNSIGNED",
+			reflect.Uint64:  "BIGINT UNSIGNED",
+			reflect.Float32: "FLOAT",
+			reflect.Float64: "DOUBLE",
+			reflect.String:  "VARCHAR(255)",
+		},
+		types: map[reflect.Type]string{
+			timeType:  "DATETIME(6)",
+			bytesType: "BLOB",
+		},
+	},
+	sqlgen.SQLite: {
+		kinds: map[reflect.Kind]string{
+			reflect.Bool:    "BOOLEAN",
+			reflect.Int:     "INTEGER",
+			reflect.Int8:    "INTEGER",
+			reflect.Int16:   "INTEGER",
+			reflect.Int32:   "INTEGER",
+			reflect.Int64:   "INTEGER",
+			reflect.Uint:    "INTEGER",
+			reflect.Uint8:   "INTEGER",
+			reflect.Uint16:  "INTEGER",
+			reflect.Uint32:  "INTEGER",
+			reflect.Uint64:  "INTEGER",
+			reflect.Float32: "REAL",
+			reflect.Float64: "REAL",
+			reflect.String:  "TEXT",
+		},
+		types: map[reflect.Type]string{
+			timeType:  "DATETIME",
+			bytesType: "BLOB",
+		},
+	},
+}
+
+// columnType returns the column type for a Go type and whether it can hold NULL,
+// pointers and database/sql Null types are nullable
+func (m typeMap) columnType(t reflect.Type) (string, bool, bool) {
+	nullable := false
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	if wrapped, ok := nullTypes[t]; ok {
+		t = wrapped
+		nullable = true
+	}
+
+	if typ, ok := m.types[t]; ok {
+		return typ, nullable, true
+	}
+	if typ, ok := m.kinds[t.Kind()]; ok {
+		return typ, nullable, true
+	}
+	return "", nullable, false
+}
@@ -0,0 +1,92 @@
+package structextract
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultTag holds the default value of a field for ApplyDefaults, e.g. `default:"8080"`
+const DefaultTag = "default"
+
+// defaultOptionTag is the tag whose default option is applied by ApplyDefaults,
+// the default option of the db tag is left alone as it holds an SQL expression
+const defaultOptionTag = "json"
+
+// DefaultError is a default value that could not be converted to the type of its field
+type DefaultError struct {
+	Field   string // Field: the path of the field, e.g. Server.Port
+	Literal string // Literal: the default value as written on the tag
+	Err     error
+}
+
+func (e *DefaultError) Error() string {
+	return fmt.Sprintf("default of field %s: %q: %v", e.Field, e.Literal, e.Err)
+}
+
+// Unwrap returns the conversion error
+func (e *DefaultError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyDefaults sets the empty fields of v, a pointer to a struct, to their default value,
+// read from the default tag, e.g. `default:"8080"`, or the default option of the json tag,
+// e.g. `json:"port,default=8080"`. Fields are empty by the same rules as the omitempty tag option.
+// Embedded and nested structs are walked, nil pointers to nested structs are left nil.
+// Slices are read from comma separated lists, e.g. `default:"a,b"`
+func ApplyDefaults(v interface{}) error {
+	e := New(v).UseEmbeddedStructs(true)
+	if err := e.isValidStruct(); err != nil {
+		return err
+	}
+
+	s := reflect.ValueOf(v).Elem()
+	return e.applyDefaults(s, "")
+}
+
+func (e *Extractor) applyDefaults(s reflect.Value, path string) error {
+	for _, field := range e.fields(s) {
+		fieldPath := path + field.name
+
+		literal, ok := e.defaultValue(field.tags)
+		if !ok && isNestedStruct(field.value.Type()) {
+			val := field.value
+			if val.Kind() == reflect.Ptr {
+				if val.IsNil() {
+					continue
+				}
+				val = val.Elem()
+			}
+			if err := e.applyDefaults(val, fieldPath+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if !ok || !field.value.CanSet() || !isEmptyValue(field.value, e.useDriverValues) {
+			continue
+		}
+
+		vals := []string{literal}
+		if holdsRepeated(field.value) {
+			vals = splitList(literal)
+		}
+		if len(vals) == 0 {
+			continue
+		}
+		if err := setStrings(field.value, vals); err != nil {
+			return &DefaultError{fieldPath, literal, err}
+		}
+	}
+	return nil
+}
+
+// defaultValue returns the default tag, or the default option of the json tag
+func (e *Extractor) defaultValue(tags reflect.StructTag) (string, bool) {
+	if literal, ok := tags.Lookup(DefaultTag); ok {
+		return literal, true
+	}
+	if tag, ok := tags.Lookup(defaultOptionTag); ok {
+		_, options := e.parseOptions(tag)
+		return options.Get(defaultOption)
+	}
+	return "", false
+}
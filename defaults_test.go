@@ -0,0 +1,132 @@
+package structextract
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type defaultsTLS struct {
+	Enabled bool   `default:"true"`
+	Cert    string `json:"cert,default=server.pem"`
+}
+
+type defaultsBase struct {
+	Name string `default:"service"`
+}
+
+type defaultsConfig struct {
+	defaultsBase
+	Port     int           `json:"port,default=8080"`
+	Host     string        `default:"localhost"`
+	Timeout  time.Duration `default:"5s"`
+	Origins  []string      `default:"a.com, b.com"`
+	Ratio    *float64      `default:"0.5"`
+	Level    level         `default:"high"`
+	TLS      defaultsTLS
+	Replica  *defaultsTLS
+	Created  time.Time `db:"created,default=now()"`
+	Untagged string
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cfg := defaultsConfig{Host: "example.com"}
+	if err := ApplyDefaults(&cfg); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ratio := 0.5
+	want := defaultsConfig{
+		defaultsBase: defaultsBase{Name: "service"},
+		Port:         8080,
+		Host:         "example.com",
+		Timeout:      5 * time.Second,
+		Origins:      []string{"a.com", "b.com"},
+		Ratio:        &ratio,
+		Level:        1,
+		TLS:          defaultsTLS{Enabled: true, Cert: "server.pem"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("want %+v, got %+v", want, cfg)
+	}
+}
+
+func TestApplyDefaults_onlyEmpty(t *testing.T) {
+	cfg := defaultsConfig{
+		Port:    9090,
+		Origins: []string{"c.com"},
+		Replica: &defaultsTLS{Cert: "replica.pem"},
+	}
+	if err := ApplyDefaults(&cfg); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Port != 9090 || !reflect.DeepEqual(cfg.Origins, []string{"c.com"}) {
+		t.Fatalf("want fields that are set untouched, got %+v", cfg)
+	}
+	want := &defaultsTLS{Enabled: true, Cert: "replica.pem"}
+	if !reflect.DeepEqual(cfg.Replica, want) {
+		t.Fatalf("want %+v, got %+v", want, cfg.Replica)
+	}
+}
+
+func TestApplyDefaults_errors(t *testing.T) {
+	cfg := struct {
+		Server struct {
+			Port int `default:"http"`
+		}
+	}{}
+
+	err := ApplyDefaults(&cfg)
+	var defErr *DefaultError
+	if !errors.As(err, &defErr) {
+		t.Fatalf("want DefaultError, got %v", err)
+	}
+	if defErr.Field != "Server.Port" || defErr.Literal != "http" {
+		t.Fatalf("want Server.Port and http, got %s and %s", defErr.Field, defErr.Literal)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("want %v, got %v", strconv.ErrSyntax, err)
+	}
+
+	if err := ApplyDefaults(cfg); err == nil {
+		t.Fatal("want error for a struct that is not a pointer, got nil")
+	}
+}
+
+func TestApplyDefaults_pointerToSlice(t *testing.T) {
+	cfg := struct {
+		Hosts *[]string `default:"a,b"`
+	}{}
+	if err := ApplyDefaults(&cfg); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Hosts == nil || !reflect.DeepEqual(*cfg.Hosts, []string{"a", "b"}) {
+		t.Fatalf("want %v, got %v", []string{"a", "b"}, cfg.Hosts)
+	}
+}
+
+func TestApplyDefaults_embeddedPointer(t *testing.T) {
+	cfg := struct {
+		*defaultsBase
+		Host string `default:"localhost"`
+	}{defaultsBase: &defaultsBase{}}
+	if err := ApplyDefaults(&cfg); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Name != "service" || cfg.Host != "localhost" {
+		t.Fatalf("want service and localhost, got %s and %s", cfg.Name, cfg.Host)
+	}
+
+	nilBase := struct {
+		*defaultsBase
+		Host string `default:"localhost"`
+	}{}
+	if err := ApplyDefaults(&nilBase); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if nilBase.defaultsBase != nil || nilBase.Host != "localhost" {
+		t.Fatalf("want nil base and localhost, got %v and %s", nilBase.defaultsBase, nilBase.Host)
+	}
+}
@@ -0,0 +1,171 @@
+package structextract
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	// EnvTag is the tag read by LoadEnv unless EnvOptions.Tag is set
+	EnvTag = "env"
+
+	requiredOption = "required"
+	defaultOption  = "default"
+)
+
+// ErrEnvNotSet is the error of a variable with the required tag option that is not set
+var ErrEnvNotSet = errors.New("required variable is not set")
+
+// EnvOptions configures LoadEnv, the zero value reads the env tag from the environment
+type EnvOptions struct {
+	Tag    string                          // Tag: the tag holding the variable names, env by default
+	Prefix string                          // Prefix: prepended to every variable name, e.g. APP_
+	Lookup func(key string) (string, bool) // Lookup: returns the value of a variable, os.LookupEnv by default
+}
+
+// EnvError is a variable that is missing or could not be converted to the type of its field
+type EnvError struct {
+	Key   string // Key: the name of the variable, including the prefixes
+	Field string // Field: the path of the field, e.g. DB.Port
+	Err   error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Field, e.Err)
+}
+
+// Unwrap returns the conversion error or ErrEnvNotSet
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// EnvErrors holds every variable that could not be loaded
+type EnvErrors []*EnvError
+
+func (e EnvErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "env: " + strings.Join(msgs, "; ")
+}
+
+// LoadEnv sets the fields of cfg, a pointer to a struct, from environment variables,
+// e.g. `env:"PORT,required"` or `env:"LOG_LEVEL,default=info"`, defaults holding commas
+// go in the default tag instead, e.g. `env:"ORIGINS" default:"a.com,b.com"`.
+// Embedded structs are walked as part of their parent, tagged nested structs add their
+// tag name and an underscore to the names of their fields, e.g. `env:"DB"` reads DB_HOST.
+// Values are converted to the field types and slices are read from comma separated lists.
+// Variables that are not set leave the field untouched unless they have a default value,
+// nil pointers to nested structs are only allocated when one of their variables is set.
+// Every missing or invalid variable is reported in the returned EnvErrors
+func LoadEnv(cfg interface{}, opts *EnvOptions) error {
+	var o EnvOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Tag == "" {
+		o.Tag = EnvTag
+	}
+	if o.Lookup == nil {
+		o.Lookup = os.LookupEnv
+	}
+
+	ext := New(cfg).UseEmbeddedStructs(true)
+	if err := ext.isValidStruct(); err != nil {
+		return err
+	}
+
+	var errs EnvErrors
+	s := reflect.ValueOf(cfg).Elem()
+	ext.loadEnv(s, o, o.Prefix, "", &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// loadEnv sets the fields of s and reports whether any of their variables is set
+func (e *Extractor) loadEnv(s reflect.Value, o EnvOptions, prefix, path string, errs *EnvErrors) bool {
+	found := false
+	for _, field := range e.fieldsFromTag(s, o.Tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		key := prefix + field.TagName
+		fieldPath := path + field.Name
+
+		if isNestedStruct(field.Value.Type()) {
+			if e.loadNestedEnv(field.Value, o, key+"_", fieldPath+".", errs) {
+				found = true
+			}
+			continue
+		}
+
+		val, ok := o.Lookup(key)
+		if ok {
+			found = true
+		} else {
+			val, ok = envDefault(field)
+		}
+		if !ok {
+			if field.Options.Has(requiredOption) {
+				*errs = append(*errs, &EnvError{key, fieldPath, ErrEnvNotSet})
+			}
+			continue
+		}
+
+		vals := []string{val}
+		if holdsRepeated(field.Value) {
+			vals = splitList(val)
+		}
+		if err := field.SetStrings(vals); err != nil {
+			*errs = append(*errs, &EnvError{key, fieldPath, err})
+		}
+	}
+	return found
+}
+
+// envDefault returns the default tag, or the default option of the env tag.
+// The option ends at the next comma, defaults holding commas need the default tag
+func envDefault(field Field) (string, bool) {
+	if literal, ok := field.tags.Lookup(DefaultTag); ok {
+		return literal, true
+	}
+	return field.Options.Get(defaultOption)
+}
+
+// loadNestedEnv sets a nested struct, a nil pointer is only allocated, and its errors
+// reported, when one of its variables is set
+func (e *Extractor) loadNestedEnv(val reflect.Value, o EnvOptions, prefix, path string, errs *EnvErrors) bool {
+	if val.Kind() != reflect.Ptr {
+		return e.loadEnv(val, o, prefix, path, errs)
+	}
+	if !val.IsNil() {
+		return e.loadEnv(val.Elem(), o, prefix, path, errs)
+	}
+
+	nested := reflect.New(val.Type().Elem())
+	var nestedErrs EnvErrors
+	if !e.loadEnv(nested.Elem(), o, prefix, path, &nestedErrs) {
+		return false
+	}
+	val.Set(nested)
+	*errs = append(*errs, nestedErrs...)
+	return true
+}
+
+// isNestedStruct reports whether typ is a struct, or a pointer to one, that is not parsed from text
+func isNestedStruct(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && !reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
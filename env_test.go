@@ -0,0 +1,180 @@
+package structextract
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type dbConfig struct {
+	Host string `env:"HOST,default=localhost"`
+	Port int    `env:"PORT,required"`
+}
+
+type logConfig struct {
+	Level string `env:"LOG_LEVEL,default=info"`
+}
+
+type appConfig struct {
+	logConfig
+	Port     int           `env:"PORT,required"`
+	Timeout  time.Duration `env:"TIMEOUT"`
+	Origins  []string      `env:"ORIGINS"`
+	Debug    *bool         `env:"DEBUG"`
+	DB       dbConfig      `env:"DB"`
+	Replica  *dbConfig     `env:"REPLICA"`
+	Internal string        `env:"-"`
+	Name     string
+}
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	env := map[string]string{
+		"APP_PORT":         "8080",
+		"APP_TIMEOUT":      "5s",
+		"APP_ORIGINS":      "a.com, b.com",
+		"APP_DEBUG":        "true",
+		"APP_DB_PORT":      "5432",
+		"APP_REPLICA_HOST": "replica",
+		"APP_REPLICA_PORT": "5433",
+		"APP_INTERNAL":     "ignored",
+	}
+
+	cfg := appConfig{Name: "keep"}
+	err := LoadEnv(&cfg, &EnvOptions{Prefix: "APP_", Lookup: lookupMap(env)})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	debug := true
+	want := appConfig{
+		logConfig: logConfig{Level: "info"},
+		Port:      8080,
+		Timeout:   5 * time.Second,
+		Origins:   []string{"a.com", "b.com"},
+		Debug:     &debug,
+		DB:        dbConfig{Host: "localhost", Port: 5432},
+		Replica:   &dbConfig{Host: "replica", Port: 5433},
+		Name:      "keep",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("want %+v, got %+v", want, cfg)
+	}
+}
+
+func TestLoadEnv_defaultTag(t *testing.T) {
+	type config struct {
+		Origins []string `env:"ORIGINS" default:"a.com,b.com"`
+	}
+
+	var cfg config
+	if err := LoadEnv(&cfg, &EnvOptions{Lookup: lookupMap(nil)}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := config{Origins: []string{"a.com", "b.com"}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("want %+v, got %+v", want, cfg)
+	}
+}
+
+func TestLoadEnv_errors(t *testing.T) {
+	env := map[string]string{
+		"TIMEOUT":      "soon",
+		"REPLICA_PORT": "x",
+	}
+
+	var cfg appConfig
+	err := LoadEnv(&cfg, &EnvOptions{Lookup: lookupMap(env)})
+
+	var errs EnvErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want EnvErrors, got %v", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Key+" "+e.Field)
+	}
+	want := []string{"PORT Port", "TIMEOUT Timeout", "DB_PORT DB.Port", "REPLICA_PORT Replica.Port"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if !errors.Is(errs[0], ErrEnvNotSet) {
+		t.Fatalf("want %v, got %v", ErrEnvNotSet, errs[0].Err)
+	}
+}
+
+func TestLoadEnv_osEnviron(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("DB_PORT", "5432")
+
+	var cfg appConfig
+	if err := LoadEnv(&cfg, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Port != 9090 || cfg.DB.Port != 5432 {
+		t.Fatalf("want 9090 and 5432, got %d and %d", cfg.Port, cfg.DB.Port)
+	}
+	if cfg.Replica != nil {
+		t.Fatalf("want nil replica without variables, got %+v", cfg.Replica)
+	}
+}
+
+func TestLoadEnv_invalid(t *testing.T) {
+	var cfg appConfig
+	if err := LoadEnv(cfg, nil); err == nil {
+		t.Fatal("want error for a struct that is not a pointer, got nil")
+	}
+}
+
+func TestLoadEnv_pointerToSlice(t *testing.T) {
+	cfg := struct {
+		Hosts *[]string `env:"HOSTS"`
+	}{}
+	env := map[string]string{"HOSTS": "a, b"}
+	if err := LoadEnv(&cfg, &EnvOptions{Lookup: lookupMap(env)}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Hosts == nil || !reflect.DeepEqual(*cfg.Hosts, []string{"a", "b"}) {
+		t.Fatalf("want %v, got %v", []string{"a", "b"}, cfg.Hosts)
+	}
+}
+
+type envBase struct {
+	Region string `env:"REGION"`
+}
+
+type envLabel string
+
+func TestLoadEnv_embeddedPointer(t *testing.T) {
+	env := map[string]string{"REGION": "eu", "NAME": "api"}
+
+	cfg := struct {
+		*envBase
+		envLabel
+		Name string `env:"NAME"`
+	}{envBase: &envBase{}}
+	if err := LoadEnv(&cfg, &EnvOptions{Lookup: lookupMap(env)}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Region != "eu" || cfg.Name != "api" {
+		t.Fatalf("want eu and api, got %s and %s", cfg.Region, cfg.Name)
+	}
+
+	nilBase := struct {
+		*envBase
+		Name string `env:"NAME"`
+	}{}
+	if err := LoadEnv(&nilBase, &EnvOptions{Lookup: lookupMap(env)}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if nilBase.envBase != nil || nilBase.Name != "api" {
+		t.Fatalf("want nil base and api, got %v and %s", nilBase.envBase, nilBase.Name)
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"strings"
 )
 
-const omitEmptyOption = "omitempty"
-
 // Extractor holds the struct that we want to extract data from
 type Extractor struct {
 	StructAddr         interface{} // StructAddr: struct address
 	ignoredFields      []string    // ignoredFields: an array with all the fields to be ignored
 	useEmbeddedStructs bool
+	useDriverValues    bool
+	derefPointers      bool
+	masker             Masker
 }
 
 // New returns a new Extractor struct
@@ -22,6 +23,9 @@ func New(s interface{}) *Extractor {
 		StructAddr:         s,
 		ignoredFields:      nil,
 		useEmbeddedStructs: false,
+		useDriverValues:    false,
+		derefPointers:      false,
+		masker:             nil,
 	}
 }
 
@@ -102,8 +106,12 @@ func (e *Extractor) Values() (out []interface{}, err error) {
 	fields := e.fields(s)
 
 	for _, field := range fields {
-		out = append(out, field.value.Interface())
-
+		value, err := e.valueOf(field.value)
+		if err != nil {
+			return nil, err
+		}
+		value = e.mask(isSensitive(field.tags, nil), value)
+		out = append(out, value)
 	}
 
 	return
@@ -111,6 +119,7 @@ func (e *Extractor) Values() (out []interface{}, err error) {
 
 // ValuesFromTag returns an interface array with all the values of fields with the given tag
 // omitempty tag option will ignore empty fields
+// json, array and text tag options will serialize the values, see SetFromTagMap for the reverse
 func (e *Extractor) ValuesFromTag(tag string) (out []interface{}, err error) {
 
 	if err := e.isValidStruct(); err != nil {
@@ -118,18 +127,7 @@ func (e *Extractor) ValuesFromTag(tag string) (out []interface{}, err error) {
 	}
 
 	s := reflect.ValueOf(e.StructAddr).Elem()
-	fields := e.fields(s)
-
-	for _, field := range fields {
-		if val, ok := field.tags.Lookup(tag); ok {
-			if _, omit := e.parseOmitempty(val, field.value); omit {
-				continue
-			}
-			out = append(out, field.value.Interface())
-		}
-	}
-
-	return
+	return e.tagValues(e.taggedFields(s, tag))
 }
 
 // FieldValueMap returns a string to interface map,
@@ -146,7 +144,12 @@ func (e *Extractor) FieldValueMap() (out map[string]interface{}, err error) {
 	fields := e.fields(s)
 
 	for _, field := range fields {
-		out[field.name] = field.value.Interface()
+		value, err := e.valueOf(field.value)
+		if err != nil {
+			return nil, err
+		}
+		value = e.mask(isSensitive(field.tags, nil), value)
+		out[field.name] = value
 	}
 
 	return
@@ -156,28 +159,15 @@ func (e *Extractor) FieldValueMap() (out map[string]interface{}, err error) {
 // key: tag name for the given field
 // value: the value of the field
 // omitempty tag option will ignore empty fields
+// json, array and text tag options will serialize the values, see SetFromTagMap for the reverse
 func (e *Extractor) FieldValueFromTagMap(tag string) (out map[string]interface{}, err error) {
 
 	if err := e.isValidStruct(); err != nil {
 		return nil, err
 	}
 
-	out = make(map[string]interface{})
 	s := reflect.ValueOf(e.StructAddr).Elem()
-	fields := e.fields(s)
-
-	for _, field := range fields {
-		if val, ok := field.tags.Lookup(tag); ok {
-			key, omit := e.parseOmitempty(val, field.value)
-			if omit {
-				continue
-			}
-			out[key] = field.value.Interface()
-		}
-
-	}
-
-	return
+	return e.tagValueMap(e.taggedFields(s, tag))
 }
 
 // TagMapping returns a map that maps tagged fields from one tag to another.
@@ -227,6 +217,20 @@ func (e *Extractor) UseEmbeddedStructs(use bool) *Extractor {
 	return e
 }
 
+// UseDriverValues toggles the unwrapping of driver.Valuer fields, e.g. sql.NullString,
+// the values returned are the result of calling Value(), nil for invalid Null values
+func (e *Extractor) UseDriverValues(use bool) *Extractor {
+	e.useDriverValues = use
+	return e
+}
+
+// DereferencePointers toggles the dereferencing of pointer fields, through as many levels as needed,
+// the values returned are the pointees or nil for nil pointers
+func (e *Extractor) DereferencePointers(deref bool) *Extractor {
+	e.derefPointers = deref
+	return e
+}
+
 func (e *Extractor) isFieldNameValid(fn string) bool {
 
 	s := reflect.ValueOf(e.StructAddr).Elem()
@@ -281,8 +285,8 @@ func (e *Extractor) fields(s reflect.Value) []field {
 		}
 
 		if s.Type().Field(i).Anonymous {
-			if e.useEmbeddedStructs {
-				fields = append(fields, e.fields(s.Field(i))...)
+			if embedded, ok := embeddedStruct(s.Field(i)); ok && e.useEmbeddedStructs {
+				fields = append(fields, e.fields(embedded)...)
 			}
 			continue
 		}
@@ -296,24 +300,50 @@ func (e *Extractor) fields(s reflect.Value) []field {
 	return fields
 }
 
-func (e *Extractor) parseOptions(tag string) (string, tagOptions) {
+// embeddedStruct returns the struct of an embedded field, dereferencing pointers,
+// nil pointers and embedded types that are not structs are reported as not ok
+func embeddedStruct(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+	return val, val.Kind() == reflect.Struct
+}
+
+func (e *Extractor) parseOptions(tag string) (string, TagOptions) {
 	res := strings.Split(tag, ",")
 	return res[0], res[1:]
 }
 
 func (e *Extractor) parseOmitempty(tag string, val reflect.Value) (string, bool) {
 	tagValue, options := e.parseOptions(tag)
-	if !options.has(omitEmptyOption) {
+	if !options.Has(OmitEmptyOption) {
 		return tagValue, false
 	}
-	zero := reflect.Zero(val.Type()).Interface()
-	current := val.Interface()
-	return tagValue, reflect.DeepEqual(current, zero)
+	return tagValue, isEmptyValue(val, e.useDriverValues)
 }
 
-type tagOptions []string
+// Tag options shared by the Extractor and the sqlgen and ddl packages
+const (
+	OmitEmptyOption  = "omitempty"  // OmitEmptyOption: empty fields are left out
+	PrimaryKeyOption = "pk"         // PrimaryKeyOption: the field is part of the primary key
+	UniqueOption     = "unique"     // UniqueOption: the column has a unique constraint
+	ReadOnlyOption   = "readonly"   // ReadOnlyOption: the field is never written, see ForInsert and ForUpdate
+	InsertOnlyOption = "insertonly" // InsertOnlyOption: the field is only written on insert
+	UpdateOnlyOption = "updateonly" // UpdateOnlyOption: the field is only written on update
+	JSONOption       = "json"       // JSONOption: the value is serialized as a JSON string
+	ArrayOption      = "array"      // ArrayOption: the value is serialized as a PostgreSQL array
+	TextOption       = "text"       // TextOption: the value is serialized with encoding.TextMarshaler
+)
+
+// TagOptions holds the options that follow the name on a tag value,
+// e.g. ["omitempty"] for `json:"name,omitempty"`
+type TagOptions []string
 
-func (t tagOptions) has(opt string) bool {
+// Has reports whether the given option is set
+func (t TagOptions) Has(opt string) bool {
 	for _, option := range t {
 		if option == opt {
 			return true
@@ -321,3 +351,13 @@ func (t tagOptions) has(opt string) bool {
 	}
 	return false
 }
+
+// Get returns the value of a key=value option, e.g. "ilike" for op=ilike
+func (t TagOptions) Get(key string) (string, bool) {
+	for _, option := range t {
+		if strings.HasPrefix(option, key+"=") {
+			return option[len(key)+1:], true
+		}
+	}
+	return "", false
+}
@@ -1,6 +1,7 @@
 package structextract
 
 import (
+	"database/sql"
 	"reflect"
 	"strings"
 	"testing"
@@ -722,3 +723,182 @@ func TestExtractor_ValuesFromTagOmitempty(t *testing.T) {
 	}
 }
 
+type nullStruct struct {
+	Name     sql.NullString `db:"name,omitempty"`
+	Nickname sql.NullString `db:"nickname"`
+	Age      sql.NullInt64  `db:"age"`
+	Score    *sql.NullInt64 `db:"score"`
+	Plain    string         `db:"plain"`
+}
+
+func TestExtractor_UseDriverValues(t *testing.T) {
+	ns := nullStruct{
+		Name:     sql.NullString{String: "stale", Valid: false},
+		Nickname: sql.NullString{String: "jo", Valid: true},
+		Plain:    "plain",
+	}
+
+	res, err := New(&ns).UseDriverValues(true).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"nickname": "jo",
+		"age":      nil,
+		"score":    nil,
+		"plain":    "plain",
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+
+	values, err := New(&ns).UseDriverValues(true).Values()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expValues := []interface{}{nil, "jo", nil, nil, "plain"}
+	if !reflect.DeepEqual(values, expValues) {
+		t.Fatalf("want %v, got %v", expValues, values)
+	}
+}
+
+func TestExtractor_UseDriverValues_Off(t *testing.T) {
+	ns := nullStruct{Nickname: sql.NullString{String: "jo", Valid: true}}
+
+	res, err := New(&ns).FieldValueMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["Nickname"] != ns.Nickname {
+		t.Fatalf("want %v, got %v", ns.Nickname, res["Nickname"])
+	}
+}
+
+func TestExtractor_OmitemptyInvalidNull(t *testing.T) {
+	ns := nullStruct{Name: sql.NullString{String: "stale", Valid: false}}
+
+	names, err := New(&ns).NamesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"name", "nickname", "age", "score", "plain"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+
+	names, err = New(&ns).UseDriverValues(true).NamesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = []string{"nickname", "age", "score", "plain"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+}
+
+func TestExtractor_OmitemptyNilValuerInInterface(t *testing.T) {
+	type ifaceStruct struct {
+		X    interface{} `db:"x,omitempty"`
+		Name string      `db:"name"`
+	}
+	s := ifaceStruct{X: (*sql.NullString)(nil), Name: "john"}
+
+	for _, use := range []bool{false, true} {
+		names, err := New(&s).UseDriverValues(use).NamesFromTag("db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := []string{"x", "name"}
+		if !reflect.DeepEqual(names, exp) {
+			t.Fatalf("want %v, got %v", exp, names)
+		}
+		if _, err := New(&s).UseDriverValues(use).ValuesFromTag("db"); err != nil {
+			t.Fatal(err)
+		}
+		res, err := New(&s).UseDriverValues(use).FieldValueFromTagMap("db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 2 {
+			t.Fatalf("want %v, got %v", 2, len(res))
+		}
+	}
+
+	res, err := New(&s).UseDriverValues(true).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["x"] != nil {
+		t.Fatalf("want %v, got %v", nil, res["x"])
+	}
+}
+
+func TestExtractor_DereferencePointers(t *testing.T) {
+	name := "john"
+	namePtr := &name
+	age := 30
+	type ptrStruct struct {
+		Name    *string  `json:"name"`
+		NamePtr **string `json:"name_ptr"`
+		Age     *int     `json:"age"`
+		Missing *int     `json:"missing"`
+		Plain   bool     `json:"plain"`
+	}
+	ps := ptrStruct{Name: &name, NamePtr: &namePtr, Age: &age, Plain: true}
+
+	res, err := New(&ps).DereferencePointers(true).FieldValueFromTagMap("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"name":     "john",
+		"name_ptr": "john",
+		"age":      30,
+		"missing":  nil,
+		"plain":    true,
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+
+	values, err := New(&ps).DereferencePointers(true).Values()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expValues := []interface{}{"john", "john", 30, nil, true}
+	if !reflect.DeepEqual(values, expValues) {
+		t.Fatalf("want %v, got %v", expValues, values)
+	}
+	if values[3] != nil {
+		t.Fatalf("an untyped nil was expected, got %#v", values[3])
+	}
+
+	fieldMap, err := New(&ps).DereferencePointers(true).FieldValueMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldMap["Age"] != 30 {
+		t.Fatalf("want 30, got %v", fieldMap["Age"])
+	}
+
+	tagValues, err := New(&ps).DereferencePointers(true).ValuesFromTag("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tagValues, expValues) {
+		t.Fatalf("want %v, got %v", expValues, tagValues)
+	}
+}
+
+func TestExtractor_DereferencePointers_DriverValues(t *testing.T) {
+	score := sql.NullInt64{Int64: 5, Valid: true}
+	ns := nullStruct{Score: &score}
+
+	res, err := New(&ns).DereferencePointers(true).UseDriverValues(true).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["score"] != int64(5) {
+		t.Fatalf("want 5, got %#v", res["score"])
+	}
+}
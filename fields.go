@@ -0,0 +1,273 @@
+package structextract
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Field is a struct field that has a given tag
+type Field struct {
+	Name    string        // Name: field name as defined on the struct
+	TagName string        // TagName: the tag value without its options
+	Options TagOptions    // Options: the tag options, e.g. omitempty
+	Value   reflect.Value // Value: the value of the field
+
+	// Sensitive: the field has the redact or secret tag option or `sensitive:"true"`, see Extractor.Redact
+	Sensitive bool
+
+	driverValues bool              // driverValues: the Extractor uses driver values, see IsEmpty
+	tags         reflect.StructTag // tags: every tag of the field, e.g. for the default tag
+}
+
+// IsEmpty reports whether the field holds the zero value of its type,
+// the same rule the omitempty tag option applies
+func (f Field) IsEmpty() bool {
+	return isEmptyValue(f.Value, f.driverValues)
+}
+
+// Interface returns the value of the field as an interface{}
+func (f Field) Interface() interface{} {
+	return f.Value.Interface()
+}
+
+// SetString parses s according to the type of the field and sets it,
+// supporting encoding.TextUnmarshaler, strings, bools, numbers,
+// time.Duration and pointers to any of them
+func (f Field) SetString(s string) error {
+	if !f.Value.CanSet() {
+		return errors.New("cannot be set")
+	}
+	return setString(f.Value, s)
+}
+
+// FormatString returns the text of the field, the reverse of SetString,
+// ok is false for nil pointers and driver.Valuer values returning nil
+func (f Field) FormatString() (text string, ok bool, err error) {
+	return formatString(f.Value)
+}
+
+// OmitEmpty reports whether the field is left out by the omitempty tag option
+func (f Field) OmitEmpty() bool {
+	return f.Options.Has(OmitEmptyOption) && f.IsEmpty()
+}
+
+// SetStrings parses one or more strings according to the type of the field and sets it,
+// slices get one element per string and other fields the first one, see SetString
+func (f Field) SetStrings(vals []string) error {
+	if !f.Value.CanSet() {
+		return errors.New("cannot be set")
+	}
+	if len(vals) == 0 {
+		return nil
+	}
+	return setStrings(f.Value, vals)
+}
+
+// FieldsFromTag returns all the fields that have the given tag, in declaration order,
+// honoring the ignored fields and the embedded structs setting.
+// Empty fields with the omitempty tag option are included, use IsEmpty to skip them
+func (e *Extractor) FieldsFromTag(tag string) ([]Field, error) {
+
+	if err := e.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	return e.fieldsFromTag(s, tag), nil
+}
+
+func (e *Extractor) fieldsFromTag(s reflect.Value, tag string) []Field {
+	var out []Field
+
+	for _, field := range e.fields(s) {
+		val, ok := field.tags.Lookup(tag)
+		if !ok {
+			continue
+		}
+		name, options := e.parseOptions(val)
+		out = append(out, Field{
+			Name:      field.name,
+			TagName:   name,
+			Options:   options,
+			Value:     field.value,
+			Sensitive: isSensitive(field.tags, options),
+
+			driverValues: e.useDriverValues,
+			tags:         field.tags,
+		})
+	}
+
+	return out
+}
+
+// This function returns the fields of a struct that have the given tag,
+// skipping empty fields marked with the omitempty option
+func (e *Extractor) taggedFields(s reflect.Value, tag string) []Field {
+	var out []Field
+
+	for _, field := range e.fieldsFromTag(s, tag) {
+		if field.OmitEmpty() {
+			continue
+		}
+		out = append(out, field)
+	}
+
+	return out
+}
+
+// isEmptyValue reports whether the value is the zero value of its type,
+// with driverValues driver.Valuer values such as an invalid sql.NullString
+// are empty as well when Value() returns nil, see UseDriverValues
+func isEmptyValue(val reflect.Value, driverValues bool) bool {
+	zero := reflect.Zero(val.Type()).Interface()
+	if reflect.DeepEqual(val.Interface(), zero) {
+		return true
+	}
+	if !driverValues {
+		return false
+	}
+	if valuer, ok := valuerOf(val); ok {
+		value, err := valuer.Value()
+		return err == nil && value == nil
+	}
+	return false
+}
+
+// valuerOf returns the driver.Valuer held by val, unwrapping interfaces.
+// Nil pointers are reported as not ok, as calling Value() on them can panic
+func valuerOf(val reflect.Value) (driver.Valuer, bool) {
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+	if isNilValue(val) {
+		return nil, false
+	}
+	valuer, ok := val.Interface().(driver.Valuer)
+	return valuer, ok
+}
+
+// valueOf returns the value of a field as it is handed out,
+// pointers are dereferenced when DereferencePointers is set and
+// driver.Valuer values are unwrapped when UseDriverValues is set
+func (e *Extractor) valueOf(val reflect.Value) (interface{}, error) {
+	if e.derefPointers {
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return nil, nil
+			}
+			val = val.Elem()
+		}
+	}
+	if e.useDriverValues {
+		if valuer, ok := valuerOf(val); ok {
+			return valuer.Value()
+		}
+		if _, ok := val.Interface().(driver.Valuer); ok {
+			return nil, nil // nil pointer, see valuerOf
+		}
+	}
+	return val.Interface(), nil
+}
+
+// tagValue returns the value of a tagged field as it is handed out,
+// masked when the field is sensitive and Redact is set
+func (e *Extractor) tagValue(field Field) (interface{}, error) {
+	value, err := e.serializedValue(field)
+	if err != nil {
+		return nil, err
+	}
+	return e.mask(field.Sensitive, value), nil
+}
+
+// serializedValue returns the value of a tagged field,
+// fields with the json tag option are marshaled to a JSON string,
+// fields with the array tag option are wrapped in a PostgresArray and
+// fields with the text tag option are marshaled with encoding.TextMarshaler
+func (e *Extractor) serializedValue(field Field) (interface{}, error) {
+	switch {
+	case field.Options.Has(JSONOption):
+		if isNilValue(field.Value) {
+			return nil, nil
+		}
+		b, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		return string(b), nil
+	case field.Options.Has(ArrayOption):
+		val := field.Value
+		for val.Kind() == reflect.Ptr && !val.IsNil() {
+			val = val.Elem()
+		}
+		if isNilValue(val) {
+			return nil, nil
+		}
+		if !isList(val) {
+			return nil, fmt.Errorf("field %s: array tag option expects a slice, got %s", field.Name, val.Type())
+		}
+		return postgresArray(val), nil
+	case field.Options.Has(TextOption):
+		if isNilValue(field.Value) {
+			return nil, nil
+		}
+		m, ok := field.Interface().(encoding.TextMarshaler)
+		if !ok && field.Value.CanAddr() {
+			m, ok = field.Value.Addr().Interface().(encoding.TextMarshaler)
+		}
+		if !ok {
+			return nil, fmt.Errorf("field %s: %s does not implement encoding.TextMarshaler", field.Name, field.Value.Type())
+		}
+		b, err := m.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		return string(b), nil
+	}
+	value, err := e.valueOf(field.Value)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %v", field.Name, err)
+	}
+	return value, nil
+}
+
+func isNilValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
+
+func tagNames(fields []Field) (out []string) {
+	for _, field := range fields {
+		out = append(out, field.TagName)
+	}
+	return
+}
+
+func (e *Extractor) tagValues(fields []Field) (out []interface{}, err error) {
+	for _, field := range fields {
+		value, err := e.tagValue(field)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+	}
+	return
+}
+
+func (e *Extractor) tagValueMap(fields []Field) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := e.tagValue(field)
+		if err != nil {
+			return nil, err
+		}
+		out[field.TagName] = value
+	}
+	return out, nil
+}
@@ -0,0 +1,197 @@
+package structextract
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_FieldsFromTag(t *testing.T) {
+	vs := viewStruct{ID: 1, Name: "john"}
+	fields, err := New(&vs).IgnoreField("UpdatedBy").FieldsFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.TagName)
+	}
+	exp := []string{"id", "name", "email", "created_by", "created_at"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+
+	id := fields[0]
+	if id.Name != "ID" || id.Interface() != 1 {
+		t.Fatalf("unexpected field %+v", id)
+	}
+	if !id.Options.Has("pk") || !id.Options.Has("readonly") || id.Options.Has("omitempty") {
+		t.Fatalf("unexpected options %v", id.Options)
+	}
+	if id.IsEmpty() {
+		t.Fatal("id should not be empty")
+	}
+	if !fields[2].IsEmpty() {
+		t.Fatal("email should be empty")
+	}
+}
+
+func TestExtractor_FieldsFromTag_Invalid_Struct(t *testing.T) {
+	test := []string{"fail", "fail2"}
+	if _, err := New(&test).FieldsFromTag("db"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
+
+func TestTagOptions_Get(t *testing.T) {
+	_, options := New(nil).parseOptions("name,omitempty,op=ilike,default=a=b")
+
+	if val, ok := options.Get("op"); !ok || val != "ilike" {
+		t.Fatalf("want ilike, got %q", val)
+	}
+	if val, ok := options.Get("default"); !ok || val != "a=b" {
+		t.Fatalf("want a=b, got %q", val)
+	}
+	if _, ok := options.Get("omitempty"); ok {
+		t.Fatal("omitempty is not a key=value option")
+	}
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("low"), nil
+	case 1:
+		return []byte("high"), nil
+	}
+	return nil, errors.New("unknown level")
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 0
+	case "high":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type serializedStruct struct {
+	ID       int               `db:"id"`
+	Meta     map[string]string `db:"meta,json"`
+	Tags     []string          `db:"tags,json,omitempty"`
+	Level    level             `db:"level,text"`
+	MaxLevel *level            `db:"max_level,text"`
+}
+
+func TestExtractor_FieldValueFromTagMap_Serialized(t *testing.T) {
+	ss := serializedStruct{
+		ID:    1,
+		Meta:  map[string]string{"a": "b"},
+		Level: 1,
+	}
+	res, err := New(&ss).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"id":        1,
+		"meta":      `{"a":"b"}`,
+		"level":     "high",
+		"max_level": nil,
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_ValuesFromTag_Serialized(t *testing.T) {
+	max := level(1)
+	ss := serializedStruct{Tags: []string{"a"}, MaxLevel: &max}
+	res, err := New(&ss).ValuesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []interface{}{0, nil, `["a"]`, "low", "high"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_ValuesFromTag_SerializeError(t *testing.T) {
+	ss := serializedStruct{Level: 5}
+	_, err := New(&ss).ValuesFromTag("db")
+	if err == nil || !strings.Contains(err.Error(), "Level") {
+		t.Fatalf("expected an error for field Level, got %v", err)
+	}
+
+	type notMarshaler struct {
+		Field int `db:"field,text"`
+	}
+	if _, err := New(&notMarshaler{}).ValuesFromTag("db"); err == nil {
+		t.Fatal("expected an error for a field that is not a TextMarshaler")
+	}
+}
+
+func TestField_FormatString(t *testing.T) {
+	type source struct {
+		Count int            `db:"count,omitempty"`
+		Name  *string        `db:"name"`
+		Ref   sql.NullString `db:"ref"`
+		Code  sql.NullString `db:"code"`
+	}
+	s := source{Ref: sql.NullString{String: "r1", Valid: true}}
+	fields, err := New(&s).FieldsFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, field := range fields {
+		text, ok, err := field.FormatString()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s=%v:%q", field.TagName, ok, text))
+	}
+	want := []string{`count=true:"0"`, `name=false:""`, `ref=true:"r1"`, `code=false:""`}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if !fields[0].OmitEmpty() || fields[2].OmitEmpty() {
+		t.Fatal("want only the empty omitempty field omitted")
+	}
+}
+
+func TestField_SetString(t *testing.T) {
+	type target struct {
+		Count int     `db:"count"`
+		Name  *string `db:"name"`
+	}
+	var tg target
+	fields, err := New(&tg).FieldsFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fields[0].SetString("3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fields[1].SetString("john"); err != nil {
+		t.Fatal(err)
+	}
+	if tg.Count != 3 || tg.Name == nil || *tg.Name != "john" {
+		t.Fatalf("unexpected result %+v", tg)
+	}
+	if err := fields[0].SetString("three"); err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
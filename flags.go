@@ -0,0 +1,140 @@
+package structextract
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// UsageTag holds the help text of a flag registered by RegisterFlags
+const UsageTag = "usage"
+
+// RegisterFlags defines one flag per field of cfg with the given tag, see Extractor.RegisterFlags
+func RegisterFlags(fs *flag.FlagSet, cfg interface{}, tag string) error {
+	return New(cfg).UseEmbeddedStructs(true).RegisterFlags(fs, tag)
+}
+
+// RegisterFlags defines one flag per field with the given tag on fs, e.g. `flag:"listen-addr" usage:"address to listen on"`.
+// The current value of the field is the default and parsed values are written back into the struct.
+// Tagged nested structs prefix the names of their fields with their tag name and a dot,
+// e.g. `flag:"db"` defines -db.host, nil pointers to nested structs are allocated.
+// Slices get one element per occurrence of the flag and bools can be set without a value, e.g. -debug
+func (e *Extractor) RegisterFlags(fs *flag.FlagSet, tag string) error {
+
+	if err := e.isValidStruct(); err != nil {
+		return err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	return e.registerFlags(fs, s, tag, "")
+}
+
+func (e *Extractor) registerFlags(fs *flag.FlagSet, s reflect.Value, tag, prefix string) error {
+	for _, field := range e.fieldsFromTag(s, tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		name := prefix + field.TagName
+
+		if isNestedStruct(field.Value.Type()) {
+			val := field.Value
+			if val.Kind() == reflect.Ptr {
+				if val.IsNil() {
+					val.Set(reflect.New(val.Type().Elem()))
+				}
+				val = val.Elem()
+			}
+			if err := e.registerFlags(fs, val, tag, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !isParsable(field.Value.Type()) {
+			return fmt.Errorf("field %s: cannot use %s as a flag", field.Name, field.Value.Type())
+		}
+		usage, _ := s.Type().FieldByName(field.Name)
+		fs.Var(&flagValue{value: field.Value}, name, usage.Tag.Get(UsageTag))
+	}
+	return nil
+}
+
+// flagValue is a flag.Value that parses into a struct field
+type flagValue struct {
+	value reflect.Value
+	set   bool
+}
+
+func (f *flagValue) String() string {
+	if !f.value.IsValid() {
+		return ""
+	}
+	val := f.value
+	if val.Kind() == reflect.Ptr && isRepeatedType(val.Type().Elem()) && !val.IsNil() {
+		val = val.Elem()
+	}
+	if isRepeated(val) {
+		if val.Len() == 0 {
+			return ""
+		}
+		return fmt.Sprint(val.Interface())
+	}
+	text, _, _ := formatString(val)
+	return text
+}
+
+// Set parses s into the field, the first value of a slice replaces its default
+// and pointers to slices are allocated
+func (f *flagValue) Set(s string) error {
+	if !holdsRepeated(f.value) {
+		return setString(f.value, s)
+	}
+
+	slice := f.value
+	if slice.Kind() == reflect.Ptr {
+		if !f.set {
+			slice.Set(reflect.New(slice.Type().Elem()))
+		}
+		slice = slice.Elem()
+	}
+
+	elem := reflect.New(slice.Type().Elem()).Elem()
+	if err := setString(elem, s); err != nil {
+		return err
+	}
+	if !f.set {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 1))
+	}
+	slice.Set(reflect.Append(slice, elem))
+	f.set = true
+	return nil
+}
+
+// IsBoolFlag lets bool fields be set without a value
+func (f *flagValue) IsBoolFlag() bool {
+	typ := f.value.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Bool
+}
+
+// isParsable reports whether setString can parse into a value of typ, slices are parsed element by element
+func isParsable(typ reflect.Type) bool {
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) || typ == durationType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return isParsable(typ.Elem())
+	case reflect.Slice:
+		elem := typ.Elem().Kind()
+		return elem != reflect.Uint8 && elem != reflect.Ptr && isParsable(typ.Elem())
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package structextract
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type serverFlags struct {
+	ListenAddr string        `flag:"listen-addr" usage:"address to listen on"`
+	Timeout    time.Duration `flag:"timeout" usage:"request timeout"`
+	Debug      bool          `flag:"debug"`
+	Origins    []string      `flag:"origin" usage:"allowed origin, repeatable"`
+	Level      level         `flag:"level"`
+	DB         struct {
+		Host string `flag:"host"`
+		Port int    `flag:"port"`
+	} `flag:"db"`
+	Cache    *cacheFlags `flag:"cache"`
+	Internal string      `flag:"-"`
+	Name     string
+}
+
+type cacheFlags struct {
+	Size int `flag:"size"`
+}
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestRegisterFlags(t *testing.T) {
+	cfg := serverFlags{ListenAddr: ":8080", Origins: []string{"default.com"}}
+	fs := newFlagSet()
+	if err := RegisterFlags(fs, &cfg, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	args := []string{
+		"-timeout", "3s",
+		"-debug",
+		"-origin", "a.com",
+		"-origin", "b.com",
+		"-level", "high",
+		"-db.host", "db.local",
+		"-db.port=5432",
+		"-cache.size", "64",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := serverFlags{
+		ListenAddr: ":8080",
+		Timeout:    3 * time.Second,
+		Debug:      true,
+		Origins:    []string{"a.com", "b.com"},
+		Level:      1,
+		Cache:      &cacheFlags{Size: 64},
+	}
+	want.DB.Host = "db.local"
+	want.DB.Port = 5432
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("want %+v, got %+v", want, cfg)
+	}
+}
+
+func TestRegisterFlags_defaultsAndUsage(t *testing.T) {
+	cfg := serverFlags{ListenAddr: ":8080", Timeout: time.Second}
+	fs := newFlagSet()
+	if err := RegisterFlags(fs, &cfg, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	f := fs.Lookup("listen-addr")
+	if f == nil {
+		t.Fatal("want listen-addr flag, got nil")
+	}
+	if f.DefValue != ":8080" || f.Usage != "address to listen on" {
+		t.Fatalf("want :8080 and usage, got %q and %q", f.DefValue, f.Usage)
+	}
+	if d := fs.Lookup("timeout").DefValue; d != "1s" {
+		t.Fatalf("want %v, got %v", "1s", d)
+	}
+	for _, name := range []string{"-", "Internal", "Name"} {
+		if fs.Lookup(name) != nil {
+			t.Fatalf("want no flag %s", name)
+		}
+	}
+
+	var out strings.Builder
+	fs.SetOutput(&out)
+	fs.PrintDefaults()
+	if !strings.Contains(out.String(), "-db.port value") {
+		t.Fatalf("want -db.port in the defaults, got %s", out.String())
+	}
+}
+
+func TestExtractor_RegisterFlags_ignoredFields(t *testing.T) {
+	cfg := serverFlags{}
+	fs := newFlagSet()
+	if err := New(&cfg).IgnoreField("Debug").RegisterFlags(fs, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fs.Lookup("debug") != nil {
+		t.Fatal("want ignored field without flag")
+	}
+}
+
+func TestRegisterFlags_pointerToSlice(t *testing.T) {
+	cfg := struct {
+		Origins *[]string `flag:"origin"`
+		Ports   *[]int    `flag:"port"`
+	}{Ports: &[]int{80}}
+	fs := newFlagSet()
+	if err := RegisterFlags(fs, &cfg, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if d := fs.Lookup("port").DefValue; d != "[80]" {
+		t.Fatalf("want %v, got %v", "[80]", d)
+	}
+
+	if err := fs.Parse([]string{"-origin", "a.com", "-origin", "b.com", "-port", "8080"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cfg.Origins == nil || !reflect.DeepEqual(*cfg.Origins, []string{"a.com", "b.com"}) {
+		t.Fatalf("want %v, got %v", []string{"a.com", "b.com"}, cfg.Origins)
+	}
+	if !reflect.DeepEqual(*cfg.Ports, []int{8080}) {
+		t.Fatalf("want %v, got %v", []int{8080}, *cfg.Ports)
+	}
+}
+
+func TestRegisterFlags_errors(t *testing.T) {
+	cfg := struct {
+		Labels map[string]string `flag:"labels"`
+	}{}
+	if err := RegisterFlags(newFlagSet(), &cfg, "flag"); err == nil {
+		t.Fatal("want error for a map field, got nil")
+	}
+
+	port := struct {
+		Port int `flag:"port"`
+	}{}
+	fs := newFlagSet()
+	if err := RegisterFlags(fs, &port, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := fs.Parse([]string{"-port", "http"}); err == nil {
+		t.Fatal("want error for an invalid number, got nil")
+	}
+}
+
+type flagsBase struct {
+	Verbose bool `flag:"verbose"`
+}
+
+func TestRegisterFlags_embeddedPointer(t *testing.T) {
+	cfg := struct {
+		*flagsBase
+		Name string `flag:"name"`
+	}{flagsBase: &flagsBase{}}
+	fs := newFlagSet()
+	if err := RegisterFlags(fs, &cfg, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := fs.Parse([]string{"-verbose", "-name", "api"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !cfg.Verbose || cfg.Name != "api" {
+		t.Fatalf("want verbose and api, got %v and %s", cfg.Verbose, cfg.Name)
+	}
+
+	nilBase := struct {
+		*flagsBase
+		Name string `flag:"name"`
+	}{}
+	fs = newFlagSet()
+	if err := RegisterFlags(fs, &nilBase, "flag"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fs.Lookup("verbose") != nil || fs.Lookup("name") == nil {
+		t.Fatal("want only the name flag for a nil embedded pointer")
+	}
+}
@@ -0,0 +1,116 @@
+package structextract
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ToHeader returns the fields with the given tag as http.Header, e.g. to propagate context between services,
+// key: tag name for the given field in canonical form, e.g. x-tenant-id becomes X-Tenant-Id
+// value: the value of the field as text, slices are joined with commas
+// omitempty tag option will ignore empty fields and nil pointers are left out
+func (e *Extractor) ToHeader(tag string) (http.Header, error) {
+
+	if err := e.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	out := make(http.Header)
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.taggedFields(s, tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		val := field.Value
+		for val.Kind() == reflect.Ptr && !val.IsNil() {
+			val = val.Elem()
+		}
+
+		if !isRepeated(val) {
+			text, ok, err := formatString(val)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", field.Name, err)
+			}
+			if ok {
+				out.Set(field.TagName, e.maskText(field.Sensitive, text))
+			}
+			continue
+		}
+
+		elems := make([]string, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			text, ok, err := formatString(val.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", field.Name, err)
+			}
+			if ok {
+				elems = append(elems, text)
+			}
+		}
+		if len(elems) > 0 {
+			out.Set(field.TagName, e.maskText(field.Sensitive, strings.Join(elems, ",")))
+		}
+	}
+
+	return out, nil
+}
+
+// SetFromHeader sets the fields with the given tag from http.Header, the reverse of ToHeader.
+// Keys are matched case insensitively, slices get one element per comma separated value
+// and other fields the first value.
+// Fields without a header are left untouched
+func (e *Extractor) SetFromHeader(tag string, header http.Header) error {
+
+	if err := e.isValidStruct(); err != nil {
+		return err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.fieldsFromTag(s, tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		vals := field.HeaderValues(header)
+		if len(vals) == 0 {
+			continue
+		}
+		if err := field.SetStrings(vals); err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// HeaderValues returns the values of the header named after the field, as read by SetFromHeader,
+// comma separated values are split into their elements when the field is a slice
+func (f Field) HeaderValues(header http.Header) []string {
+	vals := header.Values(f.TagName)
+	if len(vals) > 0 && holdsRepeated(f.Value) {
+		vals = splitHeaderValues(vals)
+	}
+	return vals
+}
+
+// holdsRepeated reports whether val, or the value a pointer val points to, is repeated
+func holdsRepeated(val reflect.Value) bool {
+	typ := val.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return isRepeatedType(typ)
+}
+
+// splitHeaderValues splits comma separated header values into their elements
+func splitHeaderValues(vals []string) (out []string) {
+	for _, v := range vals {
+		for _, elem := range strings.Split(v, ",") {
+			if elem = strings.TrimSpace(elem); elem != "" {
+				out = append(out, elem)
+			}
+		}
+	}
+	return out
+}
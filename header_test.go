@@ -0,0 +1,117 @@
+package structextract
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type callContext struct {
+	TenantID string   `header:"x-tenant-id"`
+	UserID   int      `header:"X-User-ID,omitempty"`
+	Roles    []string `header:"X-Roles,omitempty"`
+	Debug    *bool    `header:"X-Debug"`
+	Level    level    `header:"X-Level"`
+	Secret   string   `header:"-"`
+	Other    string
+}
+
+func TestExtractor_ToHeader(t *testing.T) {
+	ctx := callContext{
+		TenantID: "acme",
+		Roles:    []string{"admin", "billing"},
+		Level:    1,
+		Secret:   "secret",
+	}
+
+	got, err := New(&ctx).ToHeader("header")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := http.Header{
+		"X-Tenant-Id": {"acme"},
+		"X-Roles":     {"admin,billing"},
+		"X-Level":     {"high"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestExtractor_SetFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Tenant-ID", "acme")
+	header.Set("x-user-id", "42")
+	header.Add("X-Roles", "admin, billing")
+	header.Add("X-Roles", "support")
+	header.Set("X-Debug", "true")
+	header.Set("X-Level", "high")
+
+	ctx := callContext{Other: "keep"}
+	if err := New(&ctx).SetFromHeader("header", header); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	debug := true
+	want := callContext{
+		TenantID: "acme",
+		UserID:   42,
+		Roles:    []string{"admin", "billing", "support"},
+		Debug:    &debug,
+		Level:    1,
+		Other:    "keep",
+	}
+	if !reflect.DeepEqual(ctx, want) {
+		t.Fatalf("want %+v, got %+v", want, ctx)
+	}
+}
+
+func TestExtractor_Header_roundTrip(t *testing.T) {
+	debug := false
+	in := callContext{TenantID: "acme", UserID: 7, Roles: []string{"a", "b"}, Debug: &debug, Level: 1}
+
+	header, err := New(&in).ToHeader("header")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var out callContext
+	if err := New(&out).SetFromHeader("header", header); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %+v, got %+v", in, out)
+	}
+}
+
+func TestExtractor_Header_PointerToSlice(t *testing.T) {
+	type scopes struct {
+		Scopes *[]string `header:"X-Scopes"`
+	}
+	in := scopes{Scopes: &[]string{"read", "write"}}
+
+	header, err := New(&in).ToHeader("header")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := header.Get("X-Scopes"); got != "read,write" {
+		t.Fatalf("want %v, got %v", "read,write", got)
+	}
+
+	var out scopes
+	if err := New(&out).SetFromHeader("header", header); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %v, got %v", *in.Scopes, out.Scopes)
+	}
+}
+
+func TestExtractor_SetFromHeader_error(t *testing.T) {
+	header := http.Header{"X-User-Id": {"abc"}}
+
+	var ctx callContext
+	if err := New(&ctx).SetFromHeader("header", header); err == nil {
+		t.Fatal("want error for an invalid number, got nil")
+	}
+}
@@ -0,0 +1,90 @@
+package structextract
+
+import "reflect"
+
+// KeyNamesFromTag returns an array with the tag names of the fields
+// marked as primary key with the pk tag option, e.g. `db:"id,pk"`
+// omitempty tag option will ignore empty fields
+func (e *Extractor) KeyNamesFromTag(tag string) ([]string, error) {
+	fields, err := e.keyFields(tag, true)
+	if err != nil {
+		return nil, err
+	}
+	return tagNames(fields), nil
+}
+
+// NonKeyNamesFromTag returns an array with the tag names of the fields
+// that are not marked as primary key
+// omitempty tag option will ignore empty fields
+func (e *Extractor) NonKeyNamesFromTag(tag string) ([]string, error) {
+	fields, err := e.keyFields(tag, false)
+	if err != nil {
+		return nil, err
+	}
+	return tagNames(fields), nil
+}
+
+// KeyValuesFromTag returns an interface array with the values of the fields
+// marked as primary key, in the same order as KeyNamesFromTag
+// omitempty tag option will ignore empty fields
+func (e *Extractor) KeyValuesFromTag(tag string) ([]interface{}, error) {
+	fields, err := e.keyFields(tag, true)
+	if err != nil {
+		return nil, err
+	}
+	return e.tagValues(fields)
+}
+
+// NonKeyValuesFromTag returns an interface array with the values of the fields
+// that are not marked as primary key, in the same order as NonKeyNamesFromTag
+// omitempty tag option will ignore empty fields
+func (e *Extractor) NonKeyValuesFromTag(tag string) ([]interface{}, error) {
+	fields, err := e.keyFields(tag, false)
+	if err != nil {
+		return nil, err
+	}
+	return e.tagValues(fields)
+}
+
+// KeyFieldValueMap returns a string to interface map with the primary key fields,
+// key: tag name for the given field
+// value: the value of the field
+// This is useful to build the WHERE clause of an UPDATE, even for composite keys
+// e.g. squirrel.Update("DBTable").SetMap(nonKeys).Where(squirrel.Eq(keys))
+func (e *Extractor) KeyFieldValueMap(tag string) (map[string]interface{}, error) {
+	fields, err := e.keyFields(tag, true)
+	if err != nil {
+		return nil, err
+	}
+	return e.tagValueMap(fields)
+}
+
+// NonKeyFieldValueMap returns a string to interface map with the fields
+// that are not marked as primary key,
+// key: tag name for the given field
+// value: the value of the field
+func (e *Extractor) NonKeyFieldValueMap(tag string) (map[string]interface{}, error) {
+	fields, err := e.keyFields(tag, false)
+	if err != nil {
+		return nil, err
+	}
+	return e.tagValueMap(fields)
+}
+
+// keyFields returns the tagged fields that are (or are not) part of the primary key
+func (e *Extractor) keyFields(tag string, key bool) ([]Field, error) {
+
+	if err := e.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	var out []Field
+	for _, field := range e.taggedFields(s, tag) {
+		if field.TagName != "-" && field.Options.Has(PrimaryKeyOption) == key {
+			out = append(out, field)
+		}
+	}
+
+	return out, nil
+}
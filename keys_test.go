@@ -0,0 +1,129 @@
+package structextract
+
+import (
+	"reflect"
+	"testing"
+)
+
+type keyStruct struct {
+	TenantID int    `db:"tenant_id,pk" json:"tenantId"`
+	ID       int    `db:"id,pk" json:"id"`
+	Name     string `db:"name" json:"name"`
+	Email    string `db:"email,omitempty" json:"email"`
+	Internal string
+}
+
+func fakeKeyData() *Extractor {
+	ks := keyStruct{
+		TenantID: 7,
+		ID:       42,
+		Name:     "john",
+	}
+	return New(&ks)
+}
+
+func TestExtractor_KeyNamesFromTag(t *testing.T) {
+	res, err := fakeKeyData().KeyNamesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"tenant_id", "id"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_NonKeyNamesFromTag(t *testing.T) {
+	res, err := fakeKeyData().NonKeyNamesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"name"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_KeyValuesFromTag(t *testing.T) {
+	res, err := fakeKeyData().KeyValuesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []interface{}{7, 42}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_NonKeyValuesFromTag(t *testing.T) {
+	res, err := fakeKeyData().NonKeyValuesFromTag("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []interface{}{"john"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_KeyFieldValueMap(t *testing.T) {
+	res, err := fakeKeyData().KeyFieldValueMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"tenant_id": 7,
+		"id":        42,
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_NonKeyFieldValueMap(t *testing.T) {
+	ks := keyStruct{ID: 1, Name: "john", Email: "john@example.com"}
+	res, err := New(&ks).NonKeyFieldValueMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"name":  "john",
+		"email": "john@example.com",
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_KeyFieldValueMap_NoKeysForTag(t *testing.T) {
+	res, err := fakeKeyData().KeyFieldValueMap("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("no keys were expected, got %v", res)
+	}
+}
+
+func TestExtractor_KeyFieldValueMap_Ignored(t *testing.T) {
+	res, err := fakeKeyData().IgnoreField("TenantID").KeyFieldValueMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{"id": 42}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_KeyFieldValueMap_Invalid_Struct(t *testing.T) {
+	test := []string{"fail", "fail2"}
+	ext := New(&test)
+
+	if _, err := ext.KeyFieldValueMap("db"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+	if _, err := ext.NonKeyNamesFromTag("db"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
@@ -0,0 +1,99 @@
+package structextract
+
+import (
+	"database/sql/driver"
+	"log/slog"
+	"reflect"
+)
+
+const (
+	// LogTag excludes a field from LogValue with `log:"-"` whatever tag is logged
+	LogTag = "log"
+
+	// Redacted replaces the value of sensitive fields in LogValue
+	Redacted = "REDACTED"
+)
+
+// LogValue returns v, a struct or a pointer to one, as a slog.Value group of the fields with the given tag
+// in declaration order, e.g. slog.Any("request", structextract.LogValue(&req, "json")).
+// Nested structs become sub-groups and embedded structs are logged as part of their parent.
+// Fields tagged `log:"-"` are left out, as are empty fields with the omitempty tag option,
+// and sensitive fields, e.g. `json:"password,redact"`, are logged as REDACTED, see Extractor.Redact.
+// Any other value is returned as slog.AnyValue(v)
+func LogValue(v interface{}, tag string) slog.Value {
+	val := reflect.ValueOf(v)
+	switch {
+	case val.Kind() == reflect.Struct:
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		val = ptr
+	case val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct:
+		return slog.AnyValue(v)
+	}
+
+	e := New(val.Interface()).UseEmbeddedStructs(true)
+	return e.logValue(val.Elem(), tag)
+}
+
+func (e *Extractor) logValue(s reflect.Value, tag string) slog.Value {
+	var attrs []slog.Attr
+
+	for _, field := range e.fields(s) {
+		tagValue, ok := field.tags.Lookup(tag)
+		if !ok || field.tags.Get(LogTag) == "-" || !field.value.CanInterface() {
+			continue
+		}
+		name, options := e.parseOptions(tagValue)
+		if name == "-" || options.Has(OmitEmptyOption) && isEmptyValue(field.value, e.useDriverValues) {
+			continue
+		}
+		if isSensitive(field.tags, options) {
+			attrs = append(attrs, slog.String(name, Redacted))
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: name, Value: e.logFieldValue(field.value, tag)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// logFieldValue returns the value of a field, dereferencing pointers and unwrapping
+// driver.Valuer values such as sql.NullString. Nested structs that have fields with
+// the logged tag become sub-groups, unless they are a slog.LogValuer
+func (e *Extractor) logFieldValue(val reflect.Value, tag string) slog.Value {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		if _, ok := val.Interface().(slog.LogValuer); ok {
+			return slog.AnyValue(val.Interface())
+		}
+		val = val.Elem()
+	}
+
+	switch v := val.Interface().(type) {
+	case slog.LogValuer:
+		return slog.AnyValue(v)
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return slog.AnyValue(err)
+		}
+		return slog.AnyValue(value)
+	}
+
+	if val.Kind() == reflect.Struct && isNestedStruct(val.Type()) && e.hasTag(val, tag) {
+		return e.logValue(val, tag)
+	}
+	return slog.AnyValue(val.Interface())
+}
+
+// hasTag reports whether any field of the struct s has the given tag
+func (e *Extractor) hasTag(s reflect.Value, tag string) bool {
+	for _, field := range e.fields(s) {
+		if _, ok := field.tags.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
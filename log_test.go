@@ -0,0 +1,142 @@
+package structextract
+
+import (
+	"bytes"
+	"database/sql"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type logMeta struct {
+	RequestID string `json:"request_id"`
+}
+
+type userID int
+
+func (u userID) LogValue() slog.Value {
+	return slog.StringValue("user-" + strconv.Itoa(int(u)))
+}
+
+type logRequest struct {
+	logMeta
+	Name     string      `json:"name"`
+	Password string      `json:"password,redact"`
+	Token    string      `json:"token" log:"-"`
+	Email    string      `json:"email" sensitive:"true"`
+	Age      *int        `json:"age"`
+	Nickname string      `json:"nickname,omitempty"`
+	Address  logAddress  `json:"address"`
+	Billing  *logAddress `json:"billing"`
+	User     userID      `json:"user"`
+	Created  time.Time   `json:"created"`
+	Internal string      `json:"-"`
+	Other    string
+}
+
+func logText(v slog.Value) string {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("", slog.Any("req", v))
+	return strings.TrimSpace(buf.String())
+}
+
+func TestLogValue(t *testing.T) {
+	age := 42
+	req := logRequest{
+		logMeta:  logMeta{RequestID: "abc"},
+		Name:     "gopher",
+		Password: "hunter2",
+		Token:    "secret",
+		Email:    "a@b.c",
+		Age:      &age,
+		Address:  logAddress{City: "Stockholm"},
+		User:     7,
+		Created:  time.Date(2016, 10, 10, 10, 0, 0, 0, time.UTC),
+		Internal: "internal",
+		Other:    "other",
+	}
+
+	got := logText(LogValue(&req, "json"))
+	want := "req.request_id=abc req.name=gopher req.password=REDACTED req.email=REDACTED req.age=42 req.address.city=Stockholm " +
+		"req.billing=<nil> req.user=user-7 req.created=2016-10-10T10:00:00.000Z"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestLogValue_untaggedStructs(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	req := struct {
+		Nick  sql.NullString `json:"nick"`
+		Alias sql.NullString `json:"alias"`
+		Count sql.NullInt64  `json:"count"`
+		At    point          `json:"at"`
+	}{
+		Nick:  sql.NullString{String: "bob", Valid: true},
+		Count: sql.NullInt64{Int64: 3, Valid: true},
+		At:    point{1, 2},
+	}
+
+	got := logText(LogValue(&req, "json"))
+	want := "req.nick=bob req.alias=<nil> req.count=3 req.at=\"{X:1 Y:2}\""
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestLogValue_structValue(t *testing.T) {
+	addr := logAddress{City: "Stockholm", Zip: "111 22"}
+
+	got := logText(LogValue(addr, "json"))
+	want := `req.city=Stockholm req.zip="111 22"`
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestLogValue_notStruct(t *testing.T) {
+	v := LogValue("text", "json")
+	if v.Kind() != slog.KindString || v.String() != "text" {
+		t.Fatalf("want %v, got %v", "text", v)
+	}
+
+	var nilReq *logRequest
+	if v := LogValue(nilReq, "json"); v.Kind() != slog.KindAny {
+		t.Fatalf("want %v, got %v", slog.KindAny, v.Kind())
+	}
+}
+
+func TestLogValue_embeddedPointer(t *testing.T) {
+	req := struct {
+		*logMeta
+		Name string `json:"name"`
+	}{logMeta: &logMeta{RequestID: "abc"}, Name: "gopher"}
+
+	got := logText(LogValue(&req, "json"))
+	want := "req.request_id=abc req.name=gopher"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	req.logMeta = nil
+	if got := logText(LogValue(&req, "json")); got != "req.name=gopher" {
+		t.Fatalf("want %v, got %v", "req.name=gopher", got)
+	}
+}
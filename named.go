@@ -0,0 +1,30 @@
+package structextract
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// NamedArgs returns a sql.NamedArg for each field with the given tag,
+// in declaration order, using the tag name as the argument name
+// omitempty tag option will ignore empty fields, fields tagged "-" are skipped
+func (e *Extractor) NamedArgs(tag string) (out []sql.NamedArg, err error) {
+
+	if err := e.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.taggedFields(s, tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		value, err := e.tagValue(field)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sql.Named(field.TagName, value))
+	}
+
+	return
+}
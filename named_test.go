@@ -0,0 +1,46 @@
+package structextract
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_NamedArgs(t *testing.T) {
+	ks := keyStruct{TenantID: 7, ID: 42, Name: "john"}
+	res, err := New(&ks).NamedArgs("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []sql.NamedArg{
+		sql.Named("tenant_id", 7),
+		sql.Named("id", 42),
+		sql.Named("name", "john"),
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_NamedArgs_SkipDash(t *testing.T) {
+	type dashStruct struct {
+		ID       int    `db:"id"`
+		Password string `db:"-"`
+	}
+	ds := dashStruct{ID: 42, Password: "secret"}
+	res, err := New(&ds).NamedArgs("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []sql.NamedArg{sql.Named("id", 42)}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestExtractor_NamedArgs_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if _, err := New(&test).NamedArgs("db"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
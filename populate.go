@@ -0,0 +1,142 @@
+package structextract
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SetFromTagMap sets the fields with the given tag from a map that uses the tag name as key,
+// the reverse of FieldValueFromTagMap. Keys without a field are ignored and
+// fields without a key are left untouched, a nil value sets the field to its zero value.
+// json, array and text tag options will deserialize the values from a string or []byte
+func (e *Extractor) SetFromTagMap(tag string, values map[string]interface{}) error {
+
+	if err := e.isValidStruct(); err != nil {
+		return err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.fieldsFromTag(s, tag) {
+		value, ok := values[field.TagName]
+		if !ok {
+			continue
+		}
+		if err := e.setTagValue(field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setTagValue sets a tagged field, the reverse of tagValue
+func (e *Extractor) setTagValue(field Field, value interface{}) error {
+	if !field.Value.CanSet() {
+		return fmt.Errorf("field %s: cannot be set", field.Name)
+	}
+	if value == nil {
+		field.Value.Set(reflect.Zero(field.Value.Type()))
+		return nil
+	}
+
+	var err error
+	switch {
+	case field.Options.Has(JSONOption):
+		err = setJSON(field.Value, value)
+	case field.Options.Has(ArrayOption):
+		err = setPostgresArray(field.Value, value)
+	case field.Options.Has(TextOption):
+		err = setText(field.Value, value)
+	default:
+		err = setValue(field.Value, value)
+	}
+	if err != nil {
+		return fmt.Errorf("field %s: %v", field.Name, err)
+	}
+	return nil
+}
+
+func setJSON(dst reflect.Value, value interface{}) error {
+	data, ok := asBytes(value)
+	if !ok {
+		return fmt.Errorf("expected a JSON string or []byte, got %T", value)
+	}
+	target := reflect.New(dst.Type())
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return err
+	}
+	dst.Set(target.Elem())
+	return nil
+}
+
+func setText(dst reflect.Value, value interface{}) error {
+	data, ok := asBytes(value)
+	if !ok {
+		return fmt.Errorf("expected a string or []byte, got %T", value)
+	}
+	target := reflect.New(dst.Type())
+	if dst.Kind() == reflect.Ptr {
+		target.Elem().Set(reflect.New(dst.Type().Elem()))
+		target = target.Elem()
+	}
+	u, ok := target.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%s does not implement encoding.TextUnmarshaler", dst.Type())
+	}
+	if err := u.UnmarshalText(data); err != nil {
+		return err
+	}
+	if dst.Kind() == reflect.Ptr {
+		dst.Set(target)
+	} else {
+		dst.Set(target.Elem())
+	}
+	return nil
+}
+
+// setValue assigns value to dst, using sql.Scanner when dst implements it,
+// allocating pointers and converting between compatible types, e.g. []byte to string or int64 to int
+func setValue(dst reflect.Value, value interface{}) error {
+	if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	src := reflect.ValueOf(value)
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case dst.Kind() == reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case src.Type().ConvertibleTo(dst.Type()) && !isNumberToString(src.Kind(), dst.Kind()):
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot assign %T to %s", value, dst.Type())
+	}
+	return nil
+}
+
+// isNumberToString reports a conversion that reflect allows
+// but that yields a rune instead of the number as text
+func isNumberToString(from, to reflect.Kind) bool {
+	if to != reflect.String {
+		return false
+	}
+	return from >= reflect.Int && from <= reflect.Uint64
+}
+
+func asBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
@@ -0,0 +1,128 @@
+package structextract
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_SetFromTagMap(t *testing.T) {
+	var ss serializedStruct
+	err := New(&ss).SetFromTagMap("db", map[string]interface{}{
+		"id":        int64(7),
+		"meta":      []byte(`{"a":"b"}`),
+		"tags":      `["x","y"]`,
+		"level":     "high",
+		"max_level": []byte("low"),
+		"unknown":   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	low := level(0)
+	exp := serializedStruct{
+		ID:       7,
+		Meta:     map[string]string{"a": "b"},
+		Tags:     []string{"x", "y"},
+		Level:    1,
+		MaxLevel: &low,
+	}
+	if !reflect.DeepEqual(ss, exp) {
+		t.Fatalf("want %+v, got %+v", exp, ss)
+	}
+}
+
+func TestExtractor_SetFromTagMap_RoundTrip(t *testing.T) {
+	high := level(1)
+	in := serializedStruct{
+		ID:       1,
+		Meta:     map[string]string{"k": "v"},
+		Tags:     []string{"a"},
+		Level:    1,
+		MaxLevel: &high,
+	}
+	m, err := New(&in).FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out serializedStruct
+	if err := New(&out).SetFromTagMap("db", m); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("want %+v, got %+v", in, out)
+	}
+}
+
+func TestExtractor_SetFromTagMap_Conversions(t *testing.T) {
+	type target struct {
+		Name  string  `db:"name"`
+		Count int     `db:"count"`
+		Ratio *string `db:"ratio"`
+		Kept  string  `db:"kept"`
+		Reset string  `db:"reset"`
+	}
+	tg := target{Kept: "kept", Reset: "reset"}
+	err := New(&tg).SetFromTagMap("db", map[string]interface{}{
+		"name":  []byte("john"),
+		"count": int64(3),
+		"ratio": "0.5",
+		"reset": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tg.Name != "john" || tg.Count != 3 || *tg.Ratio != "0.5" || tg.Kept != "kept" || tg.Reset != "" {
+		t.Fatalf("unexpected result %+v", tg)
+	}
+}
+
+func TestExtractor_SetFromTagMap_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		field  string
+	}{
+		{"wrong type", map[string]interface{}{"id": "seven"}, "ID"},
+		{"json from number", map[string]interface{}{"meta": 7}, "Meta"},
+		{"bad json", map[string]interface{}{"meta": "{"}, "Meta"},
+		{"bad text", map[string]interface{}{"level": "medium"}, "Level"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ss serializedStruct
+			err := New(&ss).SetFromTagMap("db", test.values)
+			if err == nil || !strings.Contains(err.Error(), test.field) {
+				t.Fatalf("expected an error for field %s, got %v", test.field, err)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetFromTagMap_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if err := New(&test).SetFromTagMap("db", nil); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
+
+func TestExtractor_SetFromTagMap_Scanner(t *testing.T) {
+	var ns nullStruct
+	err := New(&ns).SetFromTagMap("db", map[string]interface{}{
+		"nickname": "jo",
+		"age":      int64(30),
+		"score":    int64(5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns.Nickname != (sql.NullString{String: "jo", Valid: true}) || ns.Age.Int64 != 30 || !ns.Age.Valid {
+		t.Fatalf("unexpected result %+v", ns)
+	}
+	if ns.Score == nil || ns.Score.Int64 != 5 {
+		t.Fatalf("unexpected score %+v", ns.Score)
+	}
+}
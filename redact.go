@@ -0,0 +1,125 @@
+package structextract
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SensitiveTag marks a field as sensitive whatever tag is read, e.g. `sensitive:"true"`
+	SensitiveTag = "sensitive"
+
+	redactOption = "redact"
+	secretOption = "secret"
+	mask         = "****"
+)
+
+// Masker returns the replacement of a sensitive value, val is the value as it would be
+// handed out, e.g. the field value for FieldValueMap or its text for ToURLValues
+type Masker func(val interface{}) interface{}
+
+// MaskAll replaces any value with ****
+func MaskAll(val interface{}) interface{} {
+	return mask
+}
+
+// MaskLast returns a Masker that keeps the last n characters of the text of a value,
+// e.g. ****1234 for a card number, values of n characters or less are fully masked
+func MaskLast(n int) Masker {
+	return func(val interface{}) interface{} {
+		text := []rune(maskedText(val))
+		if len(text) <= n {
+			return mask
+		}
+		return mask + string(text[len(text)-n:])
+	}
+}
+
+// maskedText returns the text of a value, pointers are dereferenced
+func maskedText(val interface{}) string {
+	if val == nil {
+		return ""
+	}
+	if text, ok, err := formatString(reflect.ValueOf(val)); ok && err == nil {
+		return text
+	}
+	return fmt.Sprint(val)
+}
+
+// Redact masks the values of sensitive fields with m in Values, ValuesFromTag, FieldValueMap,
+// FieldValueFromTagMap, NamedArgs, ToURLValues and ToHeader, nil turns masking off.
+// A field is sensitive when it has the redact or secret option on any tag, e.g. `json:"card,redact"`,
+// or `sensitive:"true"`. Nil values and empty text are not masked
+func (e *Extractor) Redact(m Masker) *Extractor {
+	e.masker = m
+	return e
+}
+
+// mask returns value, or its masked replacement when the field is sensitive and Redact is set
+func (e *Extractor) mask(sensitive bool, value interface{}) interface{} {
+	if !sensitive || e.masker == nil || value == nil || isNilValue(reflect.ValueOf(value)) {
+		return value
+	}
+	return e.masker(value)
+}
+
+// maskText returns the text of a value, or the text of its masked replacement
+func (e *Extractor) maskText(sensitive bool, text string) string {
+	if !sensitive || e.masker == nil || text == "" {
+		return text
+	}
+	return fmt.Sprint(e.masker(text))
+}
+
+// isSensitive reports whether a field with the given tags is sensitive,
+// options are those of the tag being read and every other tag is checked as well
+func isSensitive(tags reflect.StructTag, options TagOptions) bool {
+	if options.Has(redactOption) || options.Has(secretOption) {
+		return true
+	}
+	if sensitive, err := strconv.ParseBool(tags.Get(SensitiveTag)); err == nil && sensitive {
+		return true
+	}
+	for _, value := range tagValues(tags) {
+		opts := TagOptions(strings.Split(value, ",")[1:])
+		if opts.Has(redactOption) || opts.Has(secretOption) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValues returns the values of every key:"value" pair of a struct tag,
+// following the conventional format parsed by reflect.StructTag.Lookup
+func tagValues(tags reflect.StructTag) (out []string) {
+	tag := string(tags)
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, `:"`)
+		if i <= 0 {
+			break
+		}
+		tag = tag[i+1:]
+
+		// find the closing quote, skipping escaped ones
+		j := 1
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:j+1])
+		if err != nil {
+			break
+		}
+		out = append(out, value)
+		tag = tag[j+1:]
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package structextract
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type payment struct {
+	ID       int     `json:"id" db:"id"`
+	Card     string  `json:"card,redact" db:"card"`
+	CVC      string  `json:"cvc,secret" db:"cvc"`
+	Email    string  `json:"email" db:"email" sensitive:"true"`
+	Nickname *string `json:"nickname" sensitive:"true"`
+	Amount   float64 `json:"amount" db:"amount" sensitive:"false"`
+}
+
+func newPayment() payment {
+	return payment{ID: 1, Card: "4111111111111234", CVC: "123", Email: "a@b.c", Amount: 9.5}
+}
+
+func TestExtractor_Redact(t *testing.T) {
+	p := newPayment()
+	ext := New(&p).Redact(MaskLast(4))
+
+	values, err := ext.Values()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := []interface{}{1, "****1234", "****", "****@b.c", (*string)(nil), 9.5}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("want %v, got %v", want, values)
+	}
+
+	fieldMap, err := ext.FieldValueMap()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fieldMap["Card"] != "****1234" || fieldMap["ID"] != 1 {
+		t.Fatalf("want masked Card and ID 1, got %v", fieldMap)
+	}
+
+	// db has no redact options, the json ones and the sensitive tag still apply
+	tagMap, err := ext.FieldValueFromTagMap("db")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	wantMap := map[string]interface{}{"id": 1, "card": "****1234", "cvc": "****", "email": "****@b.c", "amount": 9.5}
+	if !reflect.DeepEqual(tagMap, wantMap) {
+		t.Fatalf("want %v, got %v", wantMap, tagMap)
+	}
+
+	tagValues, err := ext.ValuesFromTag("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tagValues[1] != "****1234" {
+		t.Fatalf("want %v, got %v", "****1234", tagValues[1])
+	}
+}
+
+func TestExtractor_Redact_off(t *testing.T) {
+	p := newPayment()
+
+	tagMap, err := New(&p).FieldValueFromTagMap("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tagMap["card"] != p.Card {
+		t.Fatalf("want %v, got %v", p.Card, tagMap["card"])
+	}
+
+	tagMap, err = New(&p).Redact(MaskAll).Redact(nil).FieldValueFromTagMap("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tagMap["card"] != p.Card {
+		t.Fatalf("want %v, got %v", p.Card, tagMap["card"])
+	}
+}
+
+func TestExtractor_Redact_encoders(t *testing.T) {
+	p := newPayment()
+	ext := New(&p).Redact(MaskAll)
+
+	values, err := ext.ToURLValues("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	wantValues := url.Values{"id": {"1"}, "card": {"****"}, "cvc": {"****"}, "email": {"****"}, "amount": {"9.5"}}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Fatalf("want %v, got %v", wantValues, values)
+	}
+
+	header, err := ext.ToHeader("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := header.Get("Card"); got != "****" {
+		t.Fatalf("want %v, got %v", "****", got)
+	}
+	if got := header.Get("Id"); got != "1" {
+		t.Fatalf("want %v, got %v", "1", got)
+	}
+
+	args, err := ext.NamedArgs("db")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if args[1].Value != "****" {
+		t.Fatalf("want %v, got %v", "****", args[1].Value)
+	}
+}
+
+func TestMaskLast(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		want interface{}
+	}{
+		{"4111111111111234", "****1234"},
+		{"1234", "****"},
+		{"", "****"},
+		{nil, "****"},
+		{int64(123456), "****3456"},
+		{strPtr("secret-value"), "****alue"},
+	}
+	for _, test := range tests {
+		if got := MaskLast(4)(test.val); got != test.want {
+			t.Fatalf("want %v, got %v", test.want, got)
+		}
+	}
+}
+
+func TestFieldsFromTag_sensitive(t *testing.T) {
+	p := newPayment()
+	fields, err := New(&p).FieldsFromTag("json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got []bool
+	for _, field := range fields {
+		got = append(got, field.Sensitive)
+	}
+	want := []bool{false, true, true, true, true, false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestTagValues(t *testing.T) {
+	tags := reflect.StructTag(`json:"card,redact" db:"card" other:"a \"quoted\" value"`)
+	got := tagValues(tags)
+	want := []string{"card,redact", "card", `a "quoted" value`}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
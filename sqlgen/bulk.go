@@ -0,0 +1,82 @@
+package sqlgen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/iZettle/structextract"
+)
+
+// ErrNotSlice is returned when the rows passed to BulkInsert are not a slice of structs
+var ErrNotSlice = errors.New("sqlgen: a slice of structs or pointers to structs was expected")
+
+// Statement is a query together with its args
+type Statement struct {
+	Query string
+	Args  []interface{}
+}
+
+// BulkInsert returns the multi-row INSERT statements needed to insert all the rows,
+// rows have to be a slice of structs or of pointers to structs.
+// Rows are split in as many statements as needed to stay within the
+// bind parameter limit of the dialect, see Dialect.MaxParams.
+// The columns are the insertable fields, see Extractor.ForInsert, in declaration order.
+// The omitempty tag option does not apply, every row binds every column
+// e.g. INSERT INTO "users" ("name", "email") VALUES ($1, $2), ($3, $4)
+func (b *Builder) BulkInsert(table string, rows interface{}) ([]Statement, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		return nil, ErrNotSlice
+	}
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	var args []interface{}
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		if row.Kind() != reflect.Ptr {
+			row = row.Addr()
+		}
+		view := structextract.New(row.Interface()).ForInsert(b.tag).WithEmpty()
+
+		if i == 0 {
+			var err error
+			names, err = view.Names()
+			if err != nil {
+				return nil, fmt.Errorf("sqlgen: row %d: %s", i, err)
+			}
+			if len(names) == 0 {
+				return nil, ErrNoColumns
+			}
+		}
+
+		values, err := view.Values()
+		if err != nil {
+			return nil, fmt.Errorf("sqlgen: row %d: %s", i, err)
+		}
+		args = append(args, values...)
+	}
+
+	perStatement := b.dialect.MaxParams() / len(names)
+	if perStatement == 0 {
+		return nil, fmt.Errorf("sqlgen: %d columns exceed the %s limit of %d parameters",
+			len(names), b.dialect, b.dialect.MaxParams())
+	}
+
+	var out []Statement
+	for start := 0; start < rv.Len(); start += perStatement {
+		count := perStatement
+		if start+count > rv.Len() {
+			count = rv.Len() - start
+		}
+		out = append(out, Statement{
+			Query: b.insertQuery(table, names, count),
+			Args:  args[start*len(names) : (start+count)*len(names)],
+		})
+	}
+
+	return out, nil
+}
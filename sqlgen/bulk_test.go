@@ -0,0 +1,134 @@
+package sqlgen
+
+import (
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	ID    int    `db:"id,readonly"`
+	Name  string `db:"name"`
+	Price int    `db:"price"`
+}
+
+func TestBuilder_BulkInsert(t *testing.T) {
+	rows := []item{
+		{Name: "apple", Price: 1},
+		{Name: "pear", Price: 2},
+	}
+	stmts, err := New(Postgres).BulkInsert("items", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Statement{{
+		Query: `INSERT INTO "items" ("name", "price") VALUES ($1, $2), ($3, $4)`,
+		Args:  []interface{}{"apple", 1, "pear", 2},
+	}}
+	if !reflect.DeepEqual(stmts, exp) {
+		t.Fatalf("want %v, got %v", exp, stmts)
+	}
+}
+
+func TestBuilder_BulkInsert_Pointers(t *testing.T) {
+	rows := []*item{
+		{Name: "apple", Price: 1},
+		{Name: "pear", Price: 2},
+	}
+	stmts, err := New(MySQL).BulkInsert("items", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "INSERT INTO `items` (`name`, `price`) VALUES (?, ?), (?, ?)"
+	if len(stmts) != 1 || stmts[0].Query != exp {
+		t.Fatalf("want %s, got %v", exp, stmts)
+	}
+}
+
+func TestBuilder_BulkInsert_Chunks(t *testing.T) {
+	small := &Dialect{
+		name:        "small",
+		placeholder: numbered("$"),
+		openQuote:   `"`,
+		closeQuote:  `"`,
+		maxParams:   5,
+	}
+	rows := []item{
+		{Name: "a", Price: 1},
+		{Name: "b", Price: 2},
+		{Name: "c", Price: 3},
+		{Name: "d", Price: 4},
+		{Name: "e", Price: 5},
+	}
+	stmts, err := New(small).BulkInsert("items", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Statement{
+		{
+			Query: `INSERT INTO "items" ("name", "price") VALUES ($1, $2), ($3, $4)`,
+			Args:  []interface{}{"a", 1, "b", 2},
+		},
+		{
+			Query: `INSERT INTO "items" ("name", "price") VALUES ($1, $2), ($3, $4)`,
+			Args:  []interface{}{"c", 3, "d", 4},
+		},
+		{
+			Query: `INSERT INTO "items" ("name", "price") VALUES ($1, $2)`,
+			Args:  []interface{}{"e", 5},
+		},
+	}
+	if !reflect.DeepEqual(stmts, exp) {
+		t.Fatalf("want %v, got %v", exp, stmts)
+	}
+}
+
+func TestBuilder_BulkInsert_DialectLimit(t *testing.T) {
+	rows := make([]item, 40000)
+	stmts, err := New(SQLite).BulkInsert("items", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(stmts))
+	}
+	for _, stmt := range stmts {
+		if len(stmt.Args) > SQLite.MaxParams() {
+			t.Fatalf("%d args exceed the limit", len(stmt.Args))
+		}
+	}
+}
+
+func TestBuilder_BulkInsert_Omitempty(t *testing.T) {
+	type sparse struct {
+		Name  string `db:"name"`
+		Email string `db:"email,omitempty"`
+	}
+	rows := []sparse{{"john", ""}, {"jane", "jane@example.com"}}
+	stmts, err := New(Postgres).BulkInsert("users", rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Statement{{
+		Query: `INSERT INTO "users" ("name", "email") VALUES ($1, $2), ($3, $4)`,
+		Args:  []interface{}{"john", "", "jane", "jane@example.com"},
+	}}
+	if !reflect.DeepEqual(stmts, exp) {
+		t.Fatalf("want %v, got %v", exp, stmts)
+	}
+}
+
+func TestBuilder_BulkInsert_Empty(t *testing.T) {
+	stmts, err := New(Postgres).BulkInsert("items", []item{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 0 {
+		t.Fatalf("no statements were expected, got %v", stmts)
+	}
+}
+
+func TestBuilder_BulkInsert_NotSlice(t *testing.T) {
+	if _, err := New(Postgres).BulkInsert("items", item{}); err != ErrNotSlice {
+		t.Fatalf("want %v, got %v", ErrNotSlice, err)
+	}
+}
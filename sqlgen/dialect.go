@@ -0,0 +1,101 @@
+package sqlgen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect holds the placeholder and identifier quoting rules of a database
+type Dialect struct {
+	name        string
+	placeholder func(n int) string
+	openQuote   string
+	closeQuote  string
+	maxParams   int
+	upsert      upsertStyle
+	ilike       bool
+}
+
+// upsertStyle is the syntax a dialect uses to update a row that already exists on insert
+type upsertStyle int
+
+const (
+	noUpsert       upsertStyle = iota
+	onConflict                 // ON CONFLICT (...) DO UPDATE SET
+	onDuplicateKey             // ON DUPLICATE KEY UPDATE
+)
+
+var (
+	// Postgres uses $1, $2... placeholders and "double quoted" identifiers
+	Postgres = &Dialect{
+		name:        "postgres",
+		placeholder: numbered("$"),
+		openQuote:   `"`,
+		closeQuote:  `"`,
+		maxParams:   65535,
+		upsert:      onConflict,
+		ilike:       true,
+	}
+	// MySQL uses ? placeholders and `backtick quoted` identifiers
+	MySQL = &Dialect{
+		name:        "mysql",
+		placeholder: question,
+		openQuote:   "`",
+		closeQuote:  "`",
+		maxParams:   65535,
+		upsert:      onDuplicateKey,
+	}
+	// SQLite uses ? placeholders and "double quoted" identifiers
+	SQLite = &Dialect{
+		name:        "sqlite",
+		placeholder: question,
+		openQuote:   `"`,
+		closeQuote:  `"`,
+		maxParams:   32766,
+		upsert:      onConflict,
+	}
+	// SQLServer uses @p1, @p2... placeholders and [bracket quoted] identifiers
+	SQLServer = &Dialect{
+		name:        "sqlserver",
+		placeholder: numbered("@p"),
+		openQuote:   "[",
+		closeQuote:  "]",
+		maxParams:   2100,
+	}
+)
+
+// String returns the name of the dialect
+func (d *Dialect) String() string {
+	return d.name
+}
+
+// Placeholder returns the bind parameter placeholder for the nth argument, starting at 1
+func (d *Dialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+// MaxParams returns the maximum number of bind parameters allowed in a single statement
+func (d *Dialect) MaxParams() int {
+	return d.maxParams
+}
+
+// Quote quotes an identifier, escaping the quote character when it is part of the name.
+// Qualified names such as schema.table are quoted part by part
+func (d *Dialect) Quote(ident string) string {
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		escaped := strings.Replace(part, d.closeQuote, d.closeQuote+d.closeQuote, -1)
+		parts[i] = d.openQuote + escaped + d.closeQuote
+	}
+	return strings.Join(parts, ".")
+}
+
+func numbered(prefix string) func(int) string {
+	return func(n int) string {
+		return prefix + strconv.Itoa(n)
+	}
+}
+
+func question(int) string {
+	return "?"
+}
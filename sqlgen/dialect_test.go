@@ -0,0 +1,46 @@
+package sqlgen
+
+import "testing"
+
+func TestDialect_Placeholder(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{Postgres, "$3"},
+		{MySQL, "?"},
+		{SQLite, "?"},
+		{SQLServer, "@p3"},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			if got := test.dialect.Placeholder(3); got != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestDialect_Quote(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		ident    string
+		expected string
+	}{
+		{Postgres, "users", `"users"`},
+		{Postgres, "public.users", `"public"."users"`},
+		{Postgres, `we"ird`, `"we""ird"`},
+		{MySQL, "users", "`users`"},
+		{MySQL, "we`ird", "`we``ird`"},
+		{SQLite, "users", `"users"`},
+		{SQLServer, "dbo.users", "[dbo].[users]"},
+		{SQLServer, "we]ird", "[we]]ird]"},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String()+"/"+test.ident, func(t *testing.T) {
+			if got := test.dialect.Quote(test.ident); got != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package sqlgen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+const (
+	refOption  = "ref"
+	joinOption = "join"
+	// aliasSeparator separates the nested struct name from the column name in aliases
+	aliasSeparator = "__"
+)
+
+// Scanner is implemented by *sql.Row and *sql.Rows
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// join is a nested struct read from the table referenced by a foreign key
+type join struct {
+	name   string        // name: tag name of the nested struct field, used as table alias
+	table  string        // table: referenced table, from the ref tag option
+	column string        // column: referenced column, from the ref tag option
+	key    string        // key: foreign key column of the outer table
+	field  reflect.Value // field: the nested struct field
+	left   bool          // left: the nested field is a pointer, a LEFT JOIN is used
+	names  []string      // names: columns of the nested struct
+}
+
+// joinPlan holds the outer columns and the joins of a SelectJoin, shared with ScanJoin
+type joinPlan struct {
+	columns []string
+	joins   []join
+}
+
+// SelectJoin returns a SELECT statement that reads the tagged fields of the struct
+// together with the fields of its nested structs, joined through foreign keys.
+// A foreign key is a field with the ref tag option, e.g. `db:"author_id,ref=authors.id"`.
+// A nested struct field, e.g. `db:"author"`, is joined through the foreign key named
+// after it with an _id suffix, or through the column set with the join tag option.
+// Nested columns are aliased as name__column, e.g. author__name.
+// Pointers to nested structs are read with a LEFT JOIN.
+// The rows can be read back into the struct with ScanJoin
+// e.g. SELECT "posts"."id" AS "id", "author"."name" AS "author__name" FROM "posts"
+// JOIN "authors" AS "author" ON "author"."id" = "posts"."author_id"
+func (b *Builder) SelectJoin(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	plan, err := b.joinPlan(ext)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var columns []string
+	for _, name := range plan.columns {
+		columns = append(columns, b.qualified(table, name)+" AS "+b.dialect.Quote(name))
+	}
+	for _, j := range plan.joins {
+		for _, name := range j.names {
+			columns = append(columns, b.qualified(j.name, name)+" AS "+b.dialect.Quote(j.name+aliasSeparator+name))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(columns, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.dialect.Quote(table))
+	for _, j := range plan.joins {
+		if j.left {
+			sb.WriteString(" LEFT")
+		}
+		sb.WriteString(" JOIN ")
+		sb.WriteString(b.dialect.Quote(j.table))
+		sb.WriteString(" AS ")
+		sb.WriteString(b.dialect.Quote(j.name))
+		sb.WriteString(" ON ")
+		sb.WriteString(b.qualified(j.name, j.column))
+		sb.WriteString(" = ")
+		sb.WriteString(b.qualified(table, j.key))
+	}
+
+	return sb.String(), nil, nil
+}
+
+// ScanJoin reads a row of a SelectJoin statement into the struct and its nested structs.
+// Values are set as SetFromTagMap does, a nested pointer is left nil when all its columns are NULL
+func (b *Builder) ScanJoin(row Scanner, ext *structextract.Extractor) error {
+	plan, err := b.joinPlan(ext)
+	if err != nil {
+		return err
+	}
+
+	count := len(plan.columns)
+	for _, j := range plan.joins {
+		count += len(j.names)
+	}
+	values := make([]interface{}, count)
+	dest := make([]interface{}, count)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	outer := make(map[string]interface{}, len(plan.columns))
+	for i, name := range plan.columns {
+		outer[name] = values[i]
+	}
+	if err := ext.SetFromTagMap(b.tag, outer); err != nil {
+		return err
+	}
+
+	pos := len(plan.columns)
+	for _, j := range plan.joins {
+		nested := make(map[string]interface{}, len(j.names))
+		allNull := true
+		for _, name := range j.names {
+			nested[name] = values[pos]
+			allNull = allNull && values[pos] == nil
+			pos++
+		}
+
+		var ptr reflect.Value
+		if j.left {
+			if allNull {
+				j.field.Set(reflect.Zero(j.field.Type()))
+				continue
+			}
+			ptr = reflect.New(j.field.Type().Elem())
+		} else {
+			ptr = j.field.Addr()
+		}
+		if err := structextract.New(ptr.Interface()).SetFromTagMap(b.tag, nested); err != nil {
+			return fmt.Errorf("sqlgen: %s: %v", j.name, err)
+		}
+		if j.left {
+			j.field.Set(ptr)
+		}
+	}
+
+	return nil
+}
+
+func (b *Builder) joinPlan(ext *structextract.Extractor) (joinPlan, error) {
+	fields, err := b.columns(ext)
+	if err != nil {
+		return joinPlan{}, err
+	}
+
+	refs := make(map[string]string)
+	for _, field := range fields {
+		if ref, ok := field.Options.Get(refOption); ok {
+			refs[field.TagName] = ref
+		}
+	}
+
+	var plan joinPlan
+	for _, field := range fields {
+		if !isNestedStruct(field) {
+			plan.columns = append(plan.columns, field.TagName)
+			continue
+		}
+
+		key := field.TagName + "_id"
+		if val, ok := field.Options.Get(joinOption); ok {
+			key = val
+		}
+		ref, ok := refs[key]
+		if !ok {
+			plan.columns = append(plan.columns, field.TagName)
+			continue
+		}
+		dot := strings.LastIndex(ref, ".")
+		if dot <= 0 || dot == len(ref)-1 {
+			return joinPlan{}, fmt.Errorf("sqlgen: field %s: ref has to be table.column, got %q", field.Name, ref)
+		}
+
+		j := join{
+			name:   field.TagName,
+			table:  ref[:dot],
+			column: ref[dot+1:],
+			key:    key,
+			field:  field.Value,
+		}
+		nested := field.Value
+		if nested.Kind() == reflect.Ptr {
+			j.left = true
+			nested = reflect.New(nested.Type().Elem())
+		} else {
+			nested = nested.Addr()
+		}
+		nestedFields, err := b.columns(structextract.New(nested.Interface()))
+		if err != nil {
+			return joinPlan{}, err
+		}
+		for _, nf := range nestedFields {
+			j.names = append(j.names, nf.TagName)
+		}
+		plan.joins = append(plan.joins, j)
+	}
+
+	return plan, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// isNestedStruct reports whether the field holds a struct that is read through a join,
+// serialized structs and time.Time are regular columns
+func isNestedStruct(field structextract.Field) bool {
+	if field.Options.Has(structextract.JSONOption) || field.Options.Has(structextract.TextOption) {
+		return false
+	}
+	t := field.Value.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// qualified returns a column qualified by its table or alias
+func (b *Builder) qualified(table, column string) string {
+	return b.dialect.Quote(table) + "." + b.dialect.Quote(column)
+}
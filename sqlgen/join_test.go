@@ -0,0 +1,153 @@
+package sqlgen
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iZettle/structextract"
+)
+
+type author struct {
+	ID   int    `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+type post struct {
+	ID         int       `db:"id,pk"`
+	Title      string    `db:"title"`
+	AuthorID   int       `db:"author_id,ref=authors.id"`
+	Author     author    `db:"author"`
+	EditorID   *int      `db:"editor_id,ref=authors.id"`
+	Reviewer   *author   `db:"editor,join=editor_id"`
+	Published  time.Time `db:"published"`
+	Unrelated  author    `json:"unrelated"`
+	Attachment author    `db:"attachment,json"`
+}
+
+// fakeRow implements Scanner with fixed values
+type fakeRow []interface{}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if len(dest) != len(r) {
+		return errors.New("wrong number of columns")
+	}
+	for i, d := range dest {
+		*(d.(*interface{})) = r[i]
+	}
+	return nil
+}
+
+func TestBuilder_SelectJoin(t *testing.T) {
+	query, args, err := New(Postgres).SelectJoin("posts", structextract.New(&post{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `SELECT "posts"."id" AS "id", "posts"."title" AS "title", "posts"."author_id" AS "author_id", ` +
+		`"posts"."editor_id" AS "editor_id", "posts"."published" AS "published", "posts"."attachment" AS "attachment", ` +
+		`"author"."id" AS "author__id", "author"."name" AS "author__name", ` +
+		`"editor"."id" AS "editor__id", "editor"."name" AS "editor__name" ` +
+		`FROM "posts" ` +
+		`JOIN "authors" AS "author" ON "author"."id" = "posts"."author_id" ` +
+		`LEFT JOIN "authors" AS "editor" ON "editor"."id" = "posts"."editor_id"`
+	if query != exp {
+		t.Fatalf("want\n%s\ngot\n%s", exp, query)
+	}
+	if args != nil {
+		t.Fatalf("no args were expected, got %v", args)
+	}
+}
+
+func TestBuilder_SelectJoin_MySQL(t *testing.T) {
+	type simplePost struct {
+		ID       int    `db:"id"`
+		AuthorID int    `db:"author_id,ref=blog.authors.id"`
+		Author   author `db:"author"`
+	}
+	query, _, err := New(MySQL).SelectJoin("posts", structextract.New(&simplePost{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "SELECT `posts`.`id` AS `id`, `posts`.`author_id` AS `author_id`, " +
+		"`author`.`id` AS `author__id`, `author`.`name` AS `author__name` " +
+		"FROM `posts` JOIN `blog`.`authors` AS `author` ON `author`.`id` = `posts`.`author_id`"
+	if query != exp {
+		t.Fatalf("want\n%s\ngot\n%s", exp, query)
+	}
+}
+
+func TestBuilder_ScanJoin(t *testing.T) {
+	published := time.Date(2016, 10, 10, 0, 0, 0, 0, time.UTC)
+	row := fakeRow{
+		int64(1), []byte("hello"), int64(2), int64(3), published, `{"id":9,"name":"file"}`,
+		int64(2), "john",
+		int64(3), "jane",
+	}
+
+	var p post
+	if err := New(Postgres).ScanJoin(row, structextract.New(&p)); err != nil {
+		t.Fatal(err)
+	}
+	editorID := 3
+	exp := post{
+		ID:         1,
+		Title:      "hello",
+		AuthorID:   2,
+		Author:     author{ID: 2, Name: "john"},
+		EditorID:   &editorID,
+		Reviewer:   &author{ID: 3, Name: "jane"},
+		Published:  published,
+		Attachment: author{ID: 9, Name: "file"},
+	}
+	if !reflect.DeepEqual(p, exp) {
+		t.Fatalf("want %+v, got %+v", exp, p)
+	}
+}
+
+func TestBuilder_ScanJoin_LeftJoinNull(t *testing.T) {
+	row := fakeRow{
+		int64(1), "hello", int64(2), nil, time.Time{}, nil,
+		int64(2), "john",
+		nil, nil,
+	}
+
+	p := post{Reviewer: &author{ID: 5}}
+	if err := New(Postgres).ScanJoin(row, structextract.New(&p)); err != nil {
+		t.Fatal(err)
+	}
+	if p.Reviewer != nil || p.EditorID != nil {
+		t.Fatalf("reviewer should be nil, got %+v", p.Reviewer)
+	}
+	if p.Author.Name != "john" {
+		t.Fatalf("unexpected author %+v", p.Author)
+	}
+}
+
+func TestBuilder_ScanJoin_Errors(t *testing.T) {
+	var p post
+	if err := New(Postgres).ScanJoin(fakeRow{int64(1)}, structextract.New(&p)); err == nil {
+		t.Fatal("expected the scan error")
+	}
+
+	row := fakeRow{
+		int64(1), "hello", int64(2), nil, time.Time{}, nil,
+		"not a number", "john",
+		nil, nil,
+	}
+	err := New(Postgres).ScanJoin(row, structextract.New(&p))
+	if err == nil || !strings.Contains(err.Error(), "author") {
+		t.Fatalf("expected an error for the author join, got %v", err)
+	}
+}
+
+func TestBuilder_SelectJoin_InvalidRef(t *testing.T) {
+	type badPost struct {
+		AuthorID int    `db:"author_id,ref=authors"`
+		Author   author `db:"author"`
+	}
+	if _, _, err := New(Postgres).SelectJoin("posts", structextract.New(&badPost{})); err == nil {
+		t.Fatal("expected an error for a ref without column")
+	}
+}
@@ -0,0 +1,62 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Named rewrites the :name placeholders of a query into the placeholders of the dialect,
+// returning the args in the order they appear. A name used more than once gets one arg per use.
+// Postgres casts (::) and quoted strings are left untouched
+// e.g. "SELECT * FROM users WHERE id = :id" becomes "SELECT * FROM users WHERE id = $1"
+func (b *Builder) Named(query string, named []sql.NamedArg) (string, []interface{}, error) {
+	values := make(map[string]interface{}, len(named))
+	for _, arg := range named {
+		values[arg.Name] = arg.Value
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			sb.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, ok := values[name]
+			if !ok {
+				return "", nil, fmt.Errorf("sqlgen: no arg named %q", name)
+			}
+			args = append(args, value)
+			sb.WriteString(b.dialect.Placeholder(len(args)))
+			i = end - 1
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String(), args, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,66 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iZettle/structextract"
+)
+
+func TestBuilder_Named(t *testing.T) {
+	named, err := structextract.New(&membership{TenantID: 7, UserID: 42, Role: "admin"}).NamedArgs("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := "UPDATE memberships SET role = :role WHERE tenant_id = :tenant_id AND user_id = :user_id"
+
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{Postgres, "UPDATE memberships SET role = $1 WHERE tenant_id = $2 AND user_id = $3"},
+		{MySQL, "UPDATE memberships SET role = ? WHERE tenant_id = ? AND user_id = ?"},
+		{SQLServer, "UPDATE memberships SET role = @p1 WHERE tenant_id = @p2 AND user_id = @p3"},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			res, args, err := New(test.dialect).Named(query, named)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, res)
+			}
+			expArgs := []interface{}{"admin", 7, 42}
+			if !reflect.DeepEqual(args, expArgs) {
+				t.Fatalf("want %v, got %v", expArgs, args)
+			}
+		})
+	}
+}
+
+func TestBuilder_Named_CastsAndStrings(t *testing.T) {
+	named := []sql.NamedArg{sql.Named("id", 1)}
+	query := `SELECT ':skip', "a:b", created_at::date FROM t WHERE id = :id OR parent = :id`
+
+	res, args, err := New(Postgres).Named(query, named)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `SELECT ':skip', "a:b", created_at::date FROM t WHERE id = $1 OR parent = $2`
+	if res != exp {
+		t.Fatalf("want %s, got %s", exp, res)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 1}) {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+func TestBuilder_Named_Missing(t *testing.T) {
+	_, _, err := New(Postgres).Named("SELECT * FROM t WHERE id = :id", nil)
+	if err == nil || !strings.Contains(err.Error(), `"id"`) {
+		t.Fatalf("expected a missing arg error, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+// Package sqlgen builds INSERT, UPDATE, DELETE and SELECT statements
+// from the tagged fields of a struct, using the structextract Extractor.
+package sqlgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iZettle/structextract"
+)
+
+// DefaultTag is the tag that holds the column names unless another one is set
+const DefaultTag = "db"
+
+var (
+	// ErrNoColumns is returned when the struct has no column to write or read
+	ErrNoColumns = errors.New("sqlgen: no tagged columns found")
+	// ErrNoKeys is returned when a statement needs a WHERE clause
+	// but no field is marked with the pk tag option
+	ErrNoKeys = errors.New("sqlgen: no primary key columns found, use the pk tag option")
+	// ErrEmptyKey is returned when a statement filters by the primary key
+	// and one of the key fields is empty, which would match more rows than intended
+	ErrEmptyKey = errors.New("sqlgen: empty primary key field")
+)
+
+// Builder generates SQL statements for a dialect
+type Builder struct {
+	dialect *Dialect
+	tag     string
+}
+
+// New returns a new Builder for the given dialect,
+// column names are read from the db tag
+func New(d *Dialect) *Builder {
+	return &Builder{
+		dialect: d,
+		tag:     DefaultTag,
+	}
+}
+
+// WithTag sets the tag that holds the column names
+func (b *Builder) WithTag(tag string) *Builder {
+	b.tag = tag
+	return b
+}
+
+// Insert returns an INSERT statement and its args for the insertable fields,
+// see Extractor.ForInsert
+// e.g. INSERT INTO "users" ("name", "email") VALUES ($1, $2)
+func (b *Builder) Insert(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	view := ext.ForInsert(b.tag)
+	names, err := view.Names()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 {
+		return "", nil, ErrNoColumns
+	}
+	args, err := view.Values()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return b.insertQuery(table, names, 1), args, nil
+}
+
+// insertQuery returns an INSERT statement for the given columns with rows value lists
+func (b *Builder) insertQuery(table string, names []string, rows int) string {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(b.dialect.Quote(table))
+	sb.WriteString(" (")
+	sb.WriteString(b.columnList(names))
+	sb.WriteString(") VALUES ")
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		sb.WriteString(b.placeholders(i*len(names)+1, len(names)))
+		sb.WriteString(")")
+	}
+
+	return sb.String()
+}
+
+// Update returns an UPDATE statement and its args for the updatable fields,
+// see Extractor.ForUpdate, filtered by the primary key fields
+// e.g. UPDATE "users" SET "name" = $1, "email" = $2 WHERE "id" = $3
+func (b *Builder) Update(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	keys, keyArgs, err := b.keys(ext)
+	if err != nil {
+		return "", nil, err
+	}
+
+	view := ext.ForUpdate(b.tag)
+	names, err := view.Names()
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := view.Values()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var set []string
+	var args []interface{}
+	for i, name := range names {
+		if contains(keys, name) {
+			continue
+		}
+		args = append(args, values[i])
+		set = append(set, b.dialect.Quote(name)+" = "+b.dialect.Placeholder(len(args)))
+	}
+	if len(set) == 0 {
+		return "", nil, ErrNoColumns
+	}
+
+	var sb strings.Builder
+	sb.WriteString("UPDATE ")
+	sb.WriteString(b.dialect.Quote(table))
+	sb.WriteString(" SET ")
+	sb.WriteString(strings.Join(set, ", "))
+	sb.WriteString(" WHERE ")
+	sb.WriteString(b.conditions(keys, len(args)+1))
+
+	return sb.String(), append(args, keyArgs...), nil
+}
+
+// Delete returns a DELETE statement and its args filtered by the primary key fields
+// e.g. DELETE FROM "users" WHERE "id" = $1
+func (b *Builder) Delete(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	keys, args, err := b.keys(ext)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("DELETE FROM ")
+	sb.WriteString(b.dialect.Quote(table))
+	sb.WriteString(" WHERE ")
+	sb.WriteString(b.conditions(keys, 1))
+
+	return sb.String(), args, nil
+}
+
+// Select returns a SELECT statement for every tagged field, empty or not.
+// There are no args, they are returned to keep the same signature as the other statements
+// e.g. SELECT "id", "name", "email" FROM "users"
+func (b *Builder) Select(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	fields, err := b.columns(ext)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, ErrNoColumns
+	}
+
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.TagName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(b.columnList(names))
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.dialect.Quote(table))
+
+	return sb.String(), nil, nil
+}
+
+// keys returns the primary key columns and their values for a WHERE clause.
+// The omitempty tag option does not apply, every key field has to be set
+func (b *Builder) keys(ext *structextract.Extractor) ([]string, []interface{}, error) {
+	fields, err := b.columns(ext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for _, field := range fields {
+		if !field.Options.Has(structextract.PrimaryKeyOption) {
+			continue
+		}
+		if field.IsEmpty() {
+			return nil, nil, fmt.Errorf("%w: %s", ErrEmptyKey, field.Name)
+		}
+		keys = append(keys, field.TagName)
+	}
+	if len(keys) == 0 {
+		return nil, nil, ErrNoKeys
+	}
+
+	// every key field is set, so none is left out by omitempty
+	args, err := ext.KeyValuesFromTag(b.tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, args, nil
+}
+
+// columns returns the tagged fields, leaving out the fields tagged with -
+func (b *Builder) columns(ext *structextract.Extractor) ([]structextract.Field, error) {
+	fields, err := ext.FieldsFromTag(b.tag)
+	if err != nil {
+		return nil, err
+	}
+
+	out := fields[:0]
+	for _, field := range fields {
+		if field.TagName != "-" {
+			out = append(out, field)
+		}
+	}
+	return out, nil
+}
+
+// columnList returns the quoted column names separated by commas
+func (b *Builder) columnList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = b.dialect.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// placeholders returns count placeholders separated by commas, numbered from start
+func (b *Builder) placeholders(start, count int) string {
+	out := make([]string, count)
+	for i := range out {
+		out[i] = b.dialect.Placeholder(start + i)
+	}
+	return strings.Join(out, ", ")
+}
+
+// conditions returns an equality condition per column joined by AND, numbered from start
+func (b *Builder) conditions(names []string, start int) string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = b.dialect.Quote(name) + " = " + b.dialect.Placeholder(start+i)
+	}
+	return strings.Join(out, " AND ")
+}
+
+func contains(list []string, a string) bool {
+	for _, l := range list {
+		if l == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package sqlgen
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/iZettle/structextract"
+)
+
+type user struct {
+	ID        int    `db:"id,pk" json:"id"`
+	Name      string `db:"name" json:"name"`
+	Email     string `db:"email,omitempty" json:"email"`
+	CreatedBy string `db:"created_by,insertonly" json:"createdBy"`
+	CreatedAt string `db:"created_at,readonly" json:"createdAt"`
+}
+
+type membership struct {
+	TenantID int    `db:"tenant_id,pk"`
+	UserID   int    `db:"user_id,pk"`
+	Role     string `db:"role"`
+}
+
+func fakeUser() *structextract.Extractor {
+	return structextract.New(&user{
+		ID:        1,
+		Name:      "john",
+		Email:     "john@example.com",
+		CreatedBy: "admin",
+		CreatedAt: "2016-10-10",
+	})
+}
+
+func TestBuilder_Insert(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{Postgres, `INSERT INTO "users" ("id", "name", "email", "created_by") VALUES ($1, $2, $3, $4)`},
+		{MySQL, "INSERT INTO `users` (`id`, `name`, `email`, `created_by`) VALUES (?, ?, ?, ?)"},
+		{SQLite, `INSERT INTO "users" ("id", "name", "email", "created_by") VALUES (?, ?, ?, ?)`},
+		{SQLServer, `INSERT INTO [users] ([id], [name], [email], [created_by]) VALUES (@p1, @p2, @p3, @p4)`},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			query, args, err := New(test.dialect).Insert("users", fakeUser())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, query)
+			}
+			expArgs := []interface{}{1, "john", "john@example.com", "admin"}
+			if !reflect.DeepEqual(args, expArgs) {
+				t.Fatalf("want %v, got %v", expArgs, args)
+			}
+		})
+	}
+}
+
+func TestBuilder_Insert_Omitempty(t *testing.T) {
+	query, args, err := New(Postgres).Insert("users", structextract.New(&user{Name: "john"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `INSERT INTO "users" ("id", "name", "created_by") VALUES ($1, $2, $3)`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+	if len(args) != 3 {
+		t.Fatalf("expected 3 args, got %v", args)
+	}
+}
+
+func TestBuilder_Update(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{Postgres, `UPDATE "users" SET "name" = $1, "email" = $2 WHERE "id" = $3`},
+		{MySQL, "UPDATE `users` SET `name` = ?, `email` = ? WHERE `id` = ?"},
+		{SQLServer, `UPDATE [users] SET [name] = @p1, [email] = @p2 WHERE [id] = @p3`},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			query, args, err := New(test.dialect).Update("users", fakeUser())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, query)
+			}
+			expArgs := []interface{}{"john", "john@example.com", 1}
+			if !reflect.DeepEqual(args, expArgs) {
+				t.Fatalf("want %v, got %v", expArgs, args)
+			}
+		})
+	}
+}
+
+func TestBuilder_Update_CompositeKey(t *testing.T) {
+	ext := structextract.New(&membership{TenantID: 7, UserID: 42, Role: "admin"})
+	query, args, err := New(Postgres).Update("public.memberships", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `UPDATE "public"."memberships" SET "role" = $1 WHERE "tenant_id" = $2 AND "user_id" = $3`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+	expArgs := []interface{}{"admin", 7, 42}
+	if !reflect.DeepEqual(args, expArgs) {
+		t.Fatalf("want %v, got %v", expArgs, args)
+	}
+}
+
+func TestBuilder_Update_NoKeys(t *testing.T) {
+	type noKey struct {
+		Name string `db:"name"`
+	}
+	_, _, err := New(Postgres).Update("users", structextract.New(&noKey{"john"}))
+	if err != ErrNoKeys {
+		t.Fatalf("want %v, got %v", ErrNoKeys, err)
+	}
+}
+
+func TestBuilder_EmptyKey(t *testing.T) {
+	type tenantUser struct {
+		TenantID int    `db:"tenant_id,pk"`
+		ID       int    `db:"id,pk,omitempty"`
+		Name     string `db:"name"`
+	}
+	ext := structextract.New(&tenantUser{TenantID: 7, Name: "john"})
+
+	if _, _, err := New(Postgres).Update("users", ext); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("want %v, got %v", ErrEmptyKey, err)
+	}
+	if _, _, err := New(Postgres).Delete("users", ext); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("want %v, got %v", ErrEmptyKey, err)
+	}
+}
+
+func TestBuilder_IgnoredColumns(t *testing.T) {
+	type account struct {
+		ID      int    `db:"id,pk"`
+		Name    string `db:"name"`
+		Session string `db:"-"`
+	}
+	ext := structextract.New(&account{ID: 1, Name: "john", Session: "s"})
+
+	tests := []struct {
+		build    func(string, *structextract.Extractor) (string, []interface{}, error)
+		expected string
+	}{
+		{New(Postgres).Insert, `INSERT INTO "accounts" ("id", "name") VALUES ($1, $2)`},
+		{New(Postgres).Update, `UPDATE "accounts" SET "name" = $1 WHERE "id" = $2`},
+		{New(Postgres).Select, `SELECT "id", "name" FROM "accounts"`},
+	}
+	for _, test := range tests {
+		query, _, err := test.build("accounts", ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query != test.expected {
+			t.Fatalf("want %s, got %s", test.expected, query)
+		}
+	}
+}
+
+func TestBuilder_Delete(t *testing.T) {
+	ext := structextract.New(&membership{TenantID: 7, UserID: 42})
+	query, args, err := New(SQLServer).Delete("memberships", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `DELETE FROM [memberships] WHERE [tenant_id] = @p1 AND [user_id] = @p2`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+	expArgs := []interface{}{7, 42}
+	if !reflect.DeepEqual(args, expArgs) {
+		t.Fatalf("want %v, got %v", expArgs, args)
+	}
+}
+
+func TestBuilder_Select(t *testing.T) {
+	query, args, err := New(MySQL).Select("users", structextract.New(&user{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "SELECT `id`, `name`, `email`, `created_by`, `created_at` FROM `users`"
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+	if args != nil {
+		t.Fatalf("no args were expected, got %v", args)
+	}
+}
+
+func TestBuilder_WithTag(t *testing.T) {
+	query, _, err := New(Postgres).WithTag("json").Select("users", structextract.New(&user{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `SELECT "id", "name", "email", "createdBy", "createdAt" FROM "users"`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+}
+
+func TestBuilder_NoColumns(t *testing.T) {
+	type untagged struct {
+		Name string
+	}
+	_, _, err := New(Postgres).Insert("users", structextract.New(&untagged{"john"}))
+	if err != ErrNoColumns {
+		t.Fatalf("want %v, got %v", ErrNoColumns, err)
+	}
+}
+
+func TestBuilder_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if _, _, err := New(Postgres).Insert("users", structextract.New(&test)); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
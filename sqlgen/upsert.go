@@ -0,0 +1,110 @@
+package sqlgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iZettle/structextract"
+)
+
+// ErrNoConflictColumns is returned when an upsert has no column to detect conflicts on,
+// use the unique or pk tag options
+var ErrNoConflictColumns = errors.New("sqlgen: no conflict columns found, use the unique or pk tag options")
+
+// Upsert returns an INSERT statement and its args that updates the existing row on conflict.
+// The conflict columns are the fields with the unique tag option or,
+// when there are none, the fields with the pk tag option.
+// The updated columns are the insertable fields, see Extractor.ForInsert,
+// except for the conflict columns and the fields with the insertonly tag option.
+// Postgres and SQLite use ON CONFLICT (...) DO UPDATE SET, MySQL uses ON DUPLICATE KEY UPDATE
+// e.g. INSERT INTO "users" ("email", "name") VALUES ($1, $2)
+// ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name"
+func (b *Builder) Upsert(table string, ext *structextract.Extractor) (string, []interface{}, error) {
+	if b.dialect.upsert == noUpsert {
+		return "", nil, fmt.Errorf("sqlgen: upsert is not supported by %s", b.dialect)
+	}
+
+	conflict, insertOnly, err := b.conflictColumns(ext)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query, args, err := b.Insert(table, ext)
+	if err != nil {
+		return "", nil, err
+	}
+	names, err := ext.ForInsert(b.tag).Names()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var set []string
+	for _, name := range names {
+		if contains(conflict, name) || contains(insertOnly, name) {
+			continue
+		}
+		col := b.dialect.Quote(name)
+		if b.dialect.upsert == onDuplicateKey {
+			set = append(set, col+" = VALUES("+col+")")
+		} else {
+			set = append(set, col+" = EXCLUDED."+col)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(query)
+	switch b.dialect.upsert {
+	case onConflict:
+		sb.WriteString(" ON CONFLICT (")
+		sb.WriteString(b.columnList(conflict))
+		sb.WriteString(")")
+		if len(set) == 0 {
+			sb.WriteString(" DO NOTHING")
+		} else {
+			sb.WriteString(" DO UPDATE SET ")
+			sb.WriteString(strings.Join(set, ", "))
+		}
+	case onDuplicateKey:
+		if len(set) == 0 {
+			// MySQL has no DO NOTHING, assigning a column to itself has the same effect
+			col := b.dialect.Quote(conflict[0])
+			set = append(set, col+" = "+col)
+		}
+		sb.WriteString(" ON DUPLICATE KEY UPDATE ")
+		sb.WriteString(strings.Join(set, ", "))
+	}
+
+	return sb.String(), args, nil
+}
+
+// conflictColumns returns the unique (or else the pk) columns and the insertonly columns
+func (b *Builder) conflictColumns(ext *structextract.Extractor) (conflict, insertOnly []string, err error) {
+	fields, err := b.columns(ext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unique, keys []string
+	for _, field := range fields {
+		if field.Options.Has(structextract.UniqueOption) {
+			unique = append(unique, field.TagName)
+		}
+		if field.Options.Has(structextract.PrimaryKeyOption) {
+			keys = append(keys, field.TagName)
+		}
+		if field.Options.Has(structextract.InsertOnlyOption) {
+			insertOnly = append(insertOnly, field.TagName)
+		}
+	}
+
+	conflict = unique
+	if len(conflict) == 0 {
+		conflict = keys
+	}
+	if len(conflict) == 0 {
+		return nil, nil, ErrNoConflictColumns
+	}
+
+	return conflict, insertOnly, nil
+}
@@ -0,0 +1,108 @@
+package sqlgen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iZettle/structextract"
+)
+
+type account struct {
+	ID        int    `db:"id,pk,readonly"`
+	Email     string `db:"email,unique"`
+	Name      string `db:"name"`
+	CreatedBy string `db:"created_by,insertonly"`
+}
+
+func fakeAccount() *structextract.Extractor {
+	return structextract.New(&account{ID: 1, Email: "john@example.com", Name: "john", CreatedBy: "admin"})
+}
+
+func TestBuilder_Upsert(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{
+			Postgres,
+			`INSERT INTO "accounts" ("email", "name", "created_by") VALUES ($1, $2, $3) ` +
+				`ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			SQLite,
+			`INSERT INTO "accounts" ("email", "name", "created_by") VALUES (?, ?, ?) ` +
+				`ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			MySQL,
+			"INSERT INTO `accounts` (`email`, `name`, `created_by`) VALUES (?, ?, ?) " +
+				"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			query, args, err := New(test.dialect).Upsert("accounts", fakeAccount())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, query)
+			}
+			expArgs := []interface{}{"john@example.com", "john", "admin"}
+			if !reflect.DeepEqual(args, expArgs) {
+				t.Fatalf("want %v, got %v", expArgs, args)
+			}
+		})
+	}
+}
+
+func TestBuilder_Upsert_PrimaryKey(t *testing.T) {
+	ext := structextract.New(&membership{TenantID: 7, UserID: 42, Role: "admin"})
+	query, _, err := New(Postgres).Upsert("memberships", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `INSERT INTO "memberships" ("tenant_id", "user_id", "role") VALUES ($1, $2, $3) ` +
+		`ON CONFLICT ("tenant_id", "user_id") DO UPDATE SET "role" = EXCLUDED."role"`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+}
+
+func TestBuilder_Upsert_NothingToUpdate(t *testing.T) {
+	type tag struct {
+		Name string `db:"name,unique"`
+	}
+	ext := structextract.New(&tag{"go"})
+
+	query, _, err := New(Postgres).Upsert("tags", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `INSERT INTO "tags" ("name") VALUES ($1) ON CONFLICT ("name") DO NOTHING`
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+
+	query, _, err = New(MySQL).Upsert("tags", ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = "INSERT INTO `tags` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `name` = `name`"
+	if query != exp {
+		t.Fatalf("want %s, got %s", exp, query)
+	}
+}
+
+func TestBuilder_Upsert_NoConflictColumns(t *testing.T) {
+	_, _, err := New(Postgres).Upsert("items", structextract.New(&item{Name: "apple"}))
+	if err != ErrNoConflictColumns {
+		t.Fatalf("want %v, got %v", ErrNoConflictColumns, err)
+	}
+}
+
+func TestBuilder_Upsert_Unsupported(t *testing.T) {
+	if _, _, err := New(SQLServer).Upsert("accounts", fakeAccount()); err == nil {
+		t.Fatal("upsert should not be supported by sqlserver")
+	}
+}
@@ -0,0 +1,87 @@
+package sqlgen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iZettle/structextract"
+)
+
+// operators maps the values of the op tag option to SQL comparison operators
+var operators = map[string]string{
+	"eq":    "=",
+	"ne":    "<>",
+	"lt":    "<",
+	"lte":   "<=",
+	"gt":    ">",
+	"gte":   ">=",
+	"like":  "LIKE",
+	"ilike": "ILIKE",
+	"in":    "IN",
+}
+
+// Where returns a predicate and its args that matches every non-empty tagged field,
+// joined by AND. It can be used to query by example from a filter struct.
+// Fields are compared with = unless the op tag option sets another operator:
+// eq, ne, lt, lte, gt, gte, like, ilike or in, e.g. `db:"name,op=ilike"`.
+// op=in expects a slice or an array and expands one placeholder per element.
+// Args are the values of FieldValueFromTagMap, so tag options such as json and
+// extractor settings such as UseDriverValues apply, except to the op=in elements.
+// An empty predicate and no args are returned when every field is empty
+// e.g. "name" ILIKE $1 AND "age" >= $2
+func (b *Builder) Where(ext *structextract.Extractor) (string, []interface{}, error) {
+	fields, err := b.columns(ext)
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := ext.FieldValueFromTagMap(b.tag)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, field := range fields {
+		if field.IsEmpty() {
+			continue
+		}
+
+		op := "eq"
+		if val, ok := field.Options.Get("op"); ok {
+			op = val
+		}
+		if _, ok := operators[op]; !ok {
+			return "", nil, fmt.Errorf("sqlgen: field %s: unknown operator %q", field.Name, op)
+		}
+
+		col := b.dialect.Quote(field.TagName)
+		switch op {
+		case "in":
+			kind := field.Value.Kind()
+			if kind != reflect.Slice && kind != reflect.Array {
+				return "", nil, fmt.Errorf("sqlgen: field %s: op=in expects a slice, got %s", field.Name, kind)
+			}
+			if field.Value.Len() == 0 {
+				continue
+			}
+			start := len(args) + 1
+			for i := 0; i < field.Value.Len(); i++ {
+				args = append(args, field.Value.Index(i).Interface())
+			}
+			conditions = append(conditions, col+" IN ("+b.placeholders(start, field.Value.Len())+")")
+		case "ilike":
+			args = append(args, values[field.TagName])
+			if b.dialect.ilike {
+				conditions = append(conditions, col+" ILIKE "+b.dialect.Placeholder(len(args)))
+			} else {
+				conditions = append(conditions, "LOWER("+col+") LIKE LOWER("+b.dialect.Placeholder(len(args))+")")
+			}
+		default:
+			args = append(args, values[field.TagName])
+			conditions = append(conditions, col+" "+operators[op]+" "+b.dialect.Placeholder(len(args)))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
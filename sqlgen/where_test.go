@@ -0,0 +1,125 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iZettle/structextract"
+)
+
+type userFilter struct {
+	Name     string   `db:"name,op=ilike"`
+	MinAge   int      `db:"age,op=gte"`
+	Statuses []string `db:"status,op=in"`
+	Country  string   `db:"country"`
+	Active   *bool    `db:"active"`
+	Page     int
+}
+
+func TestBuilder_Where(t *testing.T) {
+	active := false
+	filter := userFilter{
+		Name:     "jo%",
+		MinAge:   18,
+		Statuses: []string{"new", "verified"},
+		Active:   &active,
+		Page:     2,
+	}
+
+	tests := []struct {
+		dialect  *Dialect
+		expected string
+	}{
+		{Postgres, `"name" ILIKE $1 AND "age" >= $2 AND "status" IN ($3, $4) AND "active" = $5`},
+		{MySQL, "LOWER(`name`) LIKE LOWER(?) AND `age` >= ? AND `status` IN (?, ?) AND `active` = ?"},
+		{SQLServer, `LOWER([name]) LIKE LOWER(@p1) AND [age] >= @p2 AND [status] IN (@p3, @p4) AND [active] = @p5`},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.String(), func(t *testing.T) {
+			pred, args, err := New(test.dialect).Where(structextract.New(&filter))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pred != test.expected {
+				t.Fatalf("want %s, got %s", test.expected, pred)
+			}
+			expArgs := []interface{}{"jo%", 18, "new", "verified", &active}
+			if !reflect.DeepEqual(args, expArgs) {
+				t.Fatalf("want %v, got %v", expArgs, args)
+			}
+		})
+	}
+}
+
+func TestBuilder_Where_Empty(t *testing.T) {
+	filter := userFilter{Statuses: []string{}}
+	pred, args, err := New(Postgres).Where(structextract.New(&filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pred != "" || args != nil {
+		t.Fatalf("an empty predicate was expected, got %q %v", pred, args)
+	}
+}
+
+func TestBuilder_Where_Equality(t *testing.T) {
+	filter := userFilter{Country: "SE"}
+	pred, args, err := New(Postgres).Where(structextract.New(&filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pred != `"country" = $1` {
+		t.Fatalf("unexpected predicate %s", pred)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"SE"}) {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+func TestBuilder_Where_SerializedArgs(t *testing.T) {
+	type tagFilter struct {
+		Tags    map[string]string `db:"tags,json"`
+		MinAge  *int              `db:"age,op=gte"`
+		Country sql.NullString    `db:"country"`
+	}
+	age := 18
+	filter := tagFilter{
+		Tags:    map[string]string{"a": "b"},
+		MinAge:  &age,
+		Country: sql.NullString{String: "SE", Valid: true},
+	}
+
+	ext := structextract.New(&filter).DereferencePointers(true).UseDriverValues(true)
+	pred, args, err := New(Postgres).Where(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pred != `"tags" = $1 AND "age" >= $2 AND "country" = $3` {
+		t.Fatalf("unexpected predicate %s", pred)
+	}
+	expArgs := []interface{}{`{"a":"b"}`, 18, "SE"}
+	if !reflect.DeepEqual(args, expArgs) {
+		t.Fatalf("want %v, got %v", expArgs, args)
+	}
+}
+
+func TestBuilder_Where_UnknownOperator(t *testing.T) {
+	type badFilter struct {
+		Name string `db:"name,op=regex"`
+	}
+	_, _, err := New(Postgres).Where(structextract.New(&badFilter{"jo"}))
+	if err == nil || !strings.Contains(err.Error(), "regex") {
+		t.Fatalf("expected an unknown operator error, got %v", err)
+	}
+}
+
+func TestBuilder_Where_InNotSlice(t *testing.T) {
+	type badFilter struct {
+		Name string `db:"name,op=in"`
+	}
+	if _, _, err := New(Postgres).Where(structextract.New(&badFilter{"jo"})); err == nil {
+		t.Fatal("expected an error for op=in on a string")
+	}
+}
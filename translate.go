@@ -0,0 +1,178 @@
+package structextract
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownFieldError is returned when an expression references a field
+// that has no mapping between the two tags
+type UnknownFieldError struct {
+	Field string // Field: the name as found in the expression
+	Tag   string // Tag: the tag the name was looked up in
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q for tag %s", e.Field, e.Tag)
+}
+
+// Order is a sort criteria translated to the target tag
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Condition is a filter criteria translated to the target tag,
+// Op is one of =, <>, <, <=, >, >=
+type Condition struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// filterOperators maps the operators accepted in filter expressions to SQL,
+// two character operators go first so that they are matched before their prefix
+var filterOperators = []struct{ expr, sql string }{
+	{"!=", "<>"},
+	{"<=", "<="},
+	{">=", ">="},
+	{"=", "="},
+	{"<", "<"},
+	{">", ">"},
+}
+
+// Translator translates sort, projection and filter expressions written with
+// the names of one tag into the names of another tag, using TagMapping as an allowlist.
+// e.g. translating "-createdAt,name" from json to db gives "created_at DESC, name ASC"
+type Translator struct {
+	from    string
+	mapping map[string]string
+}
+
+// Translator returns a Translator from the names of the from tag to the names of the to tag,
+// tag options such as omitempty are not part of the names
+func (e *Extractor) Translator(from, to string) (*Translator, error) {
+	tags, err := e.TagMapping(from, to)
+	if err != nil {
+		return nil, err
+	}
+	mapping := make(map[string]string, len(tags))
+	for fromTag, toTag := range tags {
+		name, _ := e.parseOptions(fromTag)
+		target, _ := e.parseOptions(toTag)
+		if name == "" || name == "-" || target == "" || target == "-" {
+			continue
+		}
+		mapping[name] = target
+	}
+	return &Translator{from: from, mapping: mapping}, nil
+}
+
+// Field translates a single name, an UnknownFieldError is returned if it is not mapped
+func (t *Translator) Field(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("empty field name for tag %s", t.from)
+	}
+	out, ok := t.mapping[name]
+	if !ok {
+		return "", &UnknownFieldError{Field: name, Tag: t.from}
+	}
+	return out, nil
+}
+
+// Fields translates a comma separated list of names, e.g. "id,name"
+func (t *Translator) Fields(expr string) (out []string, err error) {
+	for _, name := range splitList(expr) {
+		field, err := t.Field(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, field)
+	}
+	return
+}
+
+// Sort translates a comma separated list of names, each optionally prefixed
+// with a single - for descending or + for ascending order, e.g. "-createdAt,name"
+func (t *Translator) Sort(expr string) (out []Order, err error) {
+	for _, item := range splitList(expr) {
+		desc := strings.HasPrefix(item, "-")
+		name := item
+		if desc || strings.HasPrefix(item, "+") {
+			name = item[1:]
+		}
+		if strings.HasPrefix(name, "-") || strings.HasPrefix(name, "+") {
+			return nil, fmt.Errorf("invalid sort expression %q", item)
+		}
+		field, err := t.Field(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Order{Field: field, Desc: desc})
+	}
+	return
+}
+
+// OrderBy translates a sort expression, see Sort, to the body of an ORDER BY clause
+// e.g. "created_at DESC, name ASC"
+func (t *Translator) OrderBy(expr string) (string, error) {
+	orders, err := t.Sort(expr)
+	if err != nil {
+		return "", err
+	}
+
+	out := make([]string, len(orders))
+	for i, order := range orders {
+		if order.Desc {
+			out[i] = order.Field + " DESC"
+		} else {
+			out[i] = order.Field + " ASC"
+		}
+	}
+	return strings.Join(out, ", "), nil
+}
+
+// Filter translates a comma separated list of comparisons,
+// supported operators are =, !=, <, <=, > and >=, e.g. "status=active,age>=18"
+func (t *Translator) Filter(expr string) (out []Condition, err error) {
+	for _, item := range splitList(expr) {
+		cond, err := t.condition(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cond)
+	}
+	return
+}
+
+func (t *Translator) condition(item string) (Condition, error) {
+	for _, op := range filterOperators {
+		i := strings.Index(item, op.expr)
+		if i < 0 {
+			continue
+		}
+		name := strings.TrimSpace(item[:i])
+		// a > or < found before = means a two character operator appears later
+		if strings.ContainsAny(name, "!<>=") {
+			continue
+		}
+		field, err := t.Field(name)
+		if err != nil {
+			return Condition{}, err
+		}
+		value := strings.TrimSpace(item[i+len(op.expr):])
+		return Condition{Field: field, Op: op.sql, Value: value}, nil
+	}
+	return Condition{}, fmt.Errorf("invalid filter expression %q", item)
+}
+
+// splitList splits a comma separated list, trimming spaces and skipping empty items
+func splitList(expr string) (out []string) {
+	for _, item := range strings.Split(expr, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+package structextract
+
+import (
+	"reflect"
+	"testing"
+)
+
+type apiStruct struct {
+	ID        int    `json:"id" db:"id,pk"`
+	Name      string `json:"name,omitempty" db:"name"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+	Age       int    `json:"age" db:"age"`
+	Secret    string `json:"-" db:"secret"`
+	Internal  string `db:"internal"`
+}
+
+func fakeTranslator(t *testing.T) *Translator {
+	tr, err := New(&apiStruct{}).Translator("json", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestTranslator_Sort(t *testing.T) {
+	res, err := fakeTranslator(t).Sort("-createdAt, name,+id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Order{
+		{Field: "created_at", Desc: true},
+		{Field: "name"},
+		{Field: "id"},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestTranslator_OrderBy(t *testing.T) {
+	res, err := fakeTranslator(t).OrderBy("-createdAt,name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "created_at DESC, name ASC"
+	if res != exp {
+		t.Fatalf("want %s, got %s", exp, res)
+	}
+}
+
+func TestTranslator_Fields(t *testing.T) {
+	res, err := fakeTranslator(t).Fields("id,name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"id", "name"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestTranslator_Fields_Empty(t *testing.T) {
+	res, err := fakeTranslator(t).Fields("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("no fields were expected, got %v", res)
+	}
+}
+
+func TestTranslator_Filter(t *testing.T) {
+	res, err := fakeTranslator(t).Filter("name=john,age>=18,age<65,id!=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []Condition{
+		{Field: "name", Op: "=", Value: "john"},
+		{Field: "age", Op: ">=", Value: "18"},
+		{Field: "age", Op: "<", Value: "65"},
+		{Field: "id", Op: "<>", Value: "3"},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+}
+
+func TestTranslator_Filter_Invalid(t *testing.T) {
+	if _, err := fakeTranslator(t).Filter("name"); err == nil {
+		t.Fatal("expected an error for a filter without operator")
+	}
+}
+
+func TestTranslator_UnknownField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		call  func(tr *Translator) error
+	}{
+		{"sort", "password", func(tr *Translator) error { _, err := tr.Sort("-password"); return err }},
+		{"fields", "internal", func(tr *Translator) error { _, err := tr.Fields("id,internal"); return err }},
+		{"filter", "Secret", func(tr *Translator) error { _, err := tr.Filter("Secret=1"); return err }},
+		{"dash", "-", func(tr *Translator) error { _, err := tr.Fields("-"); return err }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.call(fakeTranslator(t))
+			unknown, ok := err.(*UnknownFieldError)
+			if !ok {
+				t.Fatalf("expected an UnknownFieldError, got %v", err)
+			}
+			if unknown.Field != test.field || unknown.Tag != "json" {
+				t.Fatalf("unexpected error %+v", unknown)
+			}
+		})
+	}
+}
+
+func TestTranslator_IgnoredAndEmptyNames(t *testing.T) {
+	type s struct {
+		A string `json:"a" db:"-"`
+		B string `json:",omitempty" db:"b"`
+		C string `json:"c" db:"c"`
+	}
+	tr, err := New(&s{}).Translator("json", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expr := range []string{"a", "-", "+", "c,-", "--c", "+-c", "-+c", "++c"} {
+		if res, err := tr.OrderBy(expr); err == nil {
+			t.Fatalf("want error for %q, got %v", expr, res)
+		}
+	}
+	if _, err := tr.Filter("=x"); err == nil {
+		t.Fatal("want error for an empty filter name, got nil")
+	}
+	if res, err := tr.OrderBy("-c"); err != nil || res != "c DESC" {
+		t.Fatalf("want c DESC, got %v %v", res, err)
+	}
+}
+
+func TestTranslator_TagOptions(t *testing.T) {
+	tr := fakeTranslator(t)
+	if res, err := tr.Fields("name,id"); err != nil || !reflect.DeepEqual(res, []string{"name", "id"}) {
+		t.Fatalf("want [name id], got %v %v", res, err)
+	}
+	if _, err := tr.Field("name,omitempty"); err == nil {
+		t.Fatal("want error for a name with options, got nil")
+	}
+}
+
+func TestTranslator_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if _, err := New(&test).Translator("json", "db"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
@@ -0,0 +1,122 @@
+package structextract
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+// ToURLValues returns the fields with the given tag as url.Values, for query strings and forms,
+// key: tag name for the given field
+// value: the value of the field as text, slices add one value per element
+// omitempty tag option will ignore empty fields and nil pointers are left out
+// e.g. ext := structextract.New(&req); values, _ := ext.ToURLValues("url"); u.RawQuery = values.Encode()
+func (e *Extractor) ToURLValues(tag string) (url.Values, error) {
+
+	if err := e.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	out := make(url.Values)
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.taggedFields(s, tag) {
+		if field.TagName == "-" {
+			continue
+		}
+		val := field.Value
+		for val.Kind() == reflect.Ptr && !val.IsNil() {
+			val = val.Elem()
+		}
+
+		if isRepeated(val) {
+			for i := 0; i < val.Len(); i++ {
+				if err := e.addURLValue(out, field, val.Index(i)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := e.addURLValue(out, field, val); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// SetFromURLValues sets the fields with the given tag from url.Values, the reverse of ToURLValues.
+// Slices get one element per value, other fields the first value.
+// Fields without a key are left untouched
+func (e *Extractor) SetFromURLValues(tag string, values url.Values) error {
+
+	if err := e.isValidStruct(); err != nil {
+		return err
+	}
+
+	s := reflect.ValueOf(e.StructAddr).Elem()
+	for _, field := range e.fieldsFromTag(s, tag) {
+		vals, ok := values[field.TagName]
+		if !ok || len(vals) == 0 || field.TagName == "-" {
+			continue
+		}
+		if err := field.SetStrings(vals); err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// setStrings parses one or more strings into dst,
+// slices get one element per string and other values the first one.
+// Pointers to slices are allocated
+func setStrings(dst reflect.Value, vals []string) error {
+	if dst.Kind() == reflect.Ptr && isRepeatedType(dst.Type().Elem()) {
+		elem := reflect.New(dst.Type().Elem())
+		if err := setStrings(elem.Elem(), vals); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	if !isRepeated(dst) {
+		return setString(dst, vals[0])
+	}
+
+	slice := reflect.MakeSlice(dst.Type(), len(vals), len(vals))
+	for i, v := range vals {
+		if err := setString(slice.Index(i), v); err != nil {
+			return fmt.Errorf("element %d: %v", i, err)
+		}
+	}
+	dst.Set(slice)
+	return nil
+}
+
+func (e *Extractor) addURLValue(values url.Values, field Field, val reflect.Value) error {
+	text, ok, err := formatString(val)
+	if err != nil {
+		return fmt.Errorf("field %s: %v", field.Name, err)
+	}
+	if ok {
+		values.Add(field.TagName, e.maskText(field.Sensitive, text))
+	}
+	return nil
+}
+
+// isRepeated reports whether the value is a slice that is written as many values,
+// []byte and encoding.TextMarshaler slices such as net.IP are single values
+func isRepeated(val reflect.Value) bool {
+	return val.IsValid() && isRepeatedType(val.Type())
+}
+
+// isRepeatedType reports whether values of typ are written as many values, see isRepeated
+func isRepeatedType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	return !typ.Implements(textMarshalerType)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
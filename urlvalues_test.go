@@ -0,0 +1,160 @@
+package structextract
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type searchRequest struct {
+	Query   string        `url:"q"`
+	Page    int           `url:"page,omitempty"`
+	Exact   bool          `url:"exact"`
+	Tags    []string      `url:"tag"`
+	IDs     []int64       `url:"id,omitempty"`
+	Since   time.Time     `url:"since,omitempty"`
+	Timeout time.Duration `url:"timeout,omitempty"`
+	Limit   *int          `url:"limit"`
+	Level   level         `url:"level"`
+	IP      net.IP        `url:"ip,omitempty"`
+	Secret  string        `url:"-"`
+	Other   string
+}
+
+func TestExtractor_ToURLValues(t *testing.T) {
+	limit := 10
+	req := searchRequest{
+		Query:   "go & structs",
+		Exact:   true,
+		Tags:    []string{"a", "b"},
+		Since:   time.Date(2016, 10, 10, 10, 0, 0, 0, time.UTC),
+		Timeout: 2 * time.Second,
+		Limit:   &limit,
+		Level:   1,
+		IP:      net.IPv4(10, 0, 0, 1),
+		Secret:  "secret",
+	}
+
+	res, err := New(&req).ToURLValues("url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := url.Values{
+		"q":       {"go & structs"},
+		"exact":   {"true"},
+		"tag":     {"a", "b"},
+		"since":   {"2016-10-10T10:00:00Z"},
+		"timeout": {"2s"},
+		"limit":   {"10"},
+		"level":   {"high"},
+		"ip":      {"10.0.0.1"},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Fatalf("want %v, got %v", exp, res)
+	}
+	if !strings.Contains(res.Encode(), "q=go+%26+structs") {
+		t.Fatalf("unexpected encoding %s", res.Encode())
+	}
+}
+
+func TestExtractor_ToURLValues_Unsupported(t *testing.T) {
+	type badRequest struct {
+		Filter map[string]string `url:"filter"`
+	}
+	_, err := New(&badRequest{Filter: map[string]string{}}).ToURLValues("url")
+	if err == nil || !strings.Contains(err.Error(), "Filter") {
+		t.Fatalf("expected an error for field Filter, got %v", err)
+	}
+}
+
+func TestExtractor_SetFromURLValues(t *testing.T) {
+	values := url.Values{
+		"q":       {"go"},
+		"page":    {"2", "3"},
+		"exact":   {"1"},
+		"tag":     {"a", "b"},
+		"id":      {"1", "2"},
+		"since":   {"2016-10-10T10:00:00Z"},
+		"timeout": {"1m"},
+		"limit":   {"5"},
+		"level":   {"high"},
+		"ip":      {"10.0.0.1"},
+		"Other":   {"x"},
+	}
+
+	var req searchRequest
+	if err := New(&req).SetFromURLValues("url", values); err != nil {
+		t.Fatal(err)
+	}
+	limit := 5
+	exp := searchRequest{
+		Query:   "go",
+		Page:    2,
+		Exact:   true,
+		Tags:    []string{"a", "b"},
+		IDs:     []int64{1, 2},
+		Since:   time.Date(2016, 10, 10, 10, 0, 0, 0, time.UTC),
+		Timeout: time.Minute,
+		Limit:   &limit,
+		Level:   1,
+		IP:      net.IPv4(10, 0, 0, 1),
+	}
+	if !reflect.DeepEqual(req, exp) {
+		t.Fatalf("want %+v, got %+v", exp, req)
+	}
+}
+
+func TestExtractor_URLValues_PointerToSlice(t *testing.T) {
+	type filter struct {
+		Tags *[]string `url:"tag"`
+		IDs  *[]int    `url:"id"`
+	}
+	in := filter{Tags: &[]string{"a", "b"}}
+
+	values, err := New(&in).ToURLValues("url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values.Add("id", "7")
+
+	var out filter
+	if err := New(&out).SetFromURLValues("url", values); err != nil {
+		t.Fatal(err)
+	}
+	if out.Tags == nil || !reflect.DeepEqual(*out.Tags, []string{"a", "b"}) {
+		t.Fatalf("want %v, got %v", *in.Tags, out.Tags)
+	}
+	if out.IDs == nil || !reflect.DeepEqual(*out.IDs, []int{7}) {
+		t.Fatalf("want %v, got %v", []int{7}, out.IDs)
+	}
+}
+
+func TestExtractor_SetFromURLValues_Errors(t *testing.T) {
+	tests := []struct {
+		values url.Values
+		field  string
+	}{
+		{url.Values{"page": {"two"}}, "Page"},
+		{url.Values{"id": {"1", "x"}}, "IDs"},
+	}
+	for _, test := range tests {
+		var req searchRequest
+		err := New(&req).SetFromURLValues("url", test.values)
+		if err == nil || !strings.Contains(err.Error(), test.field) {
+			t.Fatalf("expected an error for field %s, got %v", test.field, err)
+		}
+	}
+}
+
+func TestExtractor_URLValues_Invalid_Struct(t *testing.T) {
+	test := "test"
+	if _, err := New(&test).ToURLValues("url"); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+	if err := New(&test).SetFromURLValues("url", nil); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}
@@ -0,0 +1,104 @@
+package structextract
+
+import "reflect"
+
+// View is a filtered set of the tagged fields of an Extractor,
+// e.g. the fields that can be written on an INSERT or an UPDATE.
+// Fields tagged with - are never part of a view
+type View struct {
+	extractor *Extractor
+	tag       string
+	excluded  []string // excluded: tag options of the fields left out of the view
+	withEmpty bool
+}
+
+// ForInsert returns a view of the fields with the given tag that can be inserted,
+// fields with the readonly or updateonly tag options are left out
+// e.g. `db:"created_at,readonly"`
+func (e *Extractor) ForInsert(tag string) *View {
+	return &View{
+		extractor: e,
+		tag:       tag,
+		excluded:  []string{ReadOnlyOption, UpdateOnlyOption},
+	}
+}
+
+// ForUpdate returns a view of the fields with the given tag that can be updated,
+// fields with the readonly or insertonly tag options are left out
+// e.g. `db:"created_by,insertonly"`
+func (e *Extractor) ForUpdate(tag string) *View {
+	return &View{
+		extractor: e,
+		tag:       tag,
+		excluded:  []string{ReadOnlyOption, InsertOnlyOption},
+	}
+}
+
+// WithEmpty keeps the empty fields with the omitempty tag option in the view,
+// e.g. for the rows of a multi-row INSERT that all need the same columns
+func (v *View) WithEmpty() *View {
+	v.withEmpty = true
+	return v
+}
+
+// Names returns an array with the tag names of the fields in the view
+// omitempty tag option will ignore empty fields
+func (v *View) Names() ([]string, error) {
+	fields, err := v.fields()
+	if err != nil {
+		return nil, err
+	}
+	return tagNames(fields), nil
+}
+
+// Values returns an interface array with the values of the fields in the view,
+// in the same order as Names
+// omitempty tag option will ignore empty fields
+func (v *View) Values() ([]interface{}, error) {
+	fields, err := v.fields()
+	if err != nil {
+		return nil, err
+	}
+	return v.extractor.tagValues(fields)
+}
+
+// FieldValueMap returns a string to interface map with the fields in the view,
+// key: tag name for the given field
+// value: the value of the field
+// omitempty tag option will ignore empty fields
+func (v *View) FieldValueMap() (map[string]interface{}, error) {
+	fields, err := v.fields()
+	if err != nil {
+		return nil, err
+	}
+	return v.extractor.tagValueMap(fields)
+}
+
+func (v *View) fields() ([]Field, error) {
+
+	if err := v.extractor.isValidStruct(); err != nil {
+		return nil, err
+	}
+
+	s := reflect.ValueOf(v.extractor.StructAddr).Elem()
+	var out []Field
+	for _, field := range v.extractor.fieldsFromTag(s, v.tag) {
+		if field.TagName == "-" || !v.withEmpty && field.OmitEmpty() {
+			continue
+		}
+		if !v.isExcluded(field.Options) {
+			out = append(out, field)
+		}
+	}
+
+	return out, nil
+}
+
+func (v *View) isExcluded(options TagOptions) bool {
+	for _, opt := range v.excluded {
+		if options.Has(opt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package structextract
+
+import (
+	"reflect"
+	"testing"
+)
+
+type viewStruct struct {
+	ID        int    `db:"id,pk,readonly"`
+	Name      string `db:"name"`
+	Email     string `db:"email,omitempty"`
+	CreatedBy string `db:"created_by,insertonly"`
+	UpdatedBy string `db:"updated_by,updateonly"`
+	CreatedAt string `db:"created_at,readonly"`
+}
+
+func fakeViewData() *Extractor {
+	vs := viewStruct{
+		ID:        1,
+		Name:      "john",
+		CreatedBy: "admin",
+		UpdatedBy: "root",
+		CreatedAt: "2016-10-10",
+	}
+	return New(&vs)
+}
+
+func TestView_ForInsert(t *testing.T) {
+	view := fakeViewData().ForInsert("db")
+
+	names, err := view.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expNames := []string{"name", "created_by"}
+	if !reflect.DeepEqual(names, expNames) {
+		t.Fatalf("want %v, got %v", expNames, names)
+	}
+
+	values, err := view.Values()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expValues := []interface{}{"john", "admin"}
+	if !reflect.DeepEqual(values, expValues) {
+		t.Fatalf("want %v, got %v", expValues, values)
+	}
+
+	m, err := view.FieldValueMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expMap := map[string]interface{}{"name": "john", "created_by": "admin"}
+	if !reflect.DeepEqual(m, expMap) {
+		t.Fatalf("want %v, got %v", expMap, m)
+	}
+}
+
+func TestView_ForUpdate(t *testing.T) {
+	view := fakeViewData().ForUpdate("db")
+
+	names, err := view.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expNames := []string{"name", "updated_by"}
+	if !reflect.DeepEqual(names, expNames) {
+		t.Fatalf("want %v, got %v", expNames, names)
+	}
+
+	m, err := view.FieldValueMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expMap := map[string]interface{}{"name": "john", "updated_by": "root"}
+	if !reflect.DeepEqual(m, expMap) {
+		t.Fatalf("want %v, got %v", expMap, m)
+	}
+}
+
+func TestView_Omitempty(t *testing.T) {
+	vs := viewStruct{Email: "john@example.com"}
+	names, err := New(&vs).ForUpdate("db").Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"name", "email", "updated_by"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+}
+
+func TestView_WithEmpty(t *testing.T) {
+	names, err := New(&viewStruct{}).ForInsert("db").WithEmpty().Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"name", "email", "created_by"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+}
+
+func TestView_Ignored(t *testing.T) {
+	names, err := fakeViewData().IgnoreField("CreatedBy").ForInsert("db").Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"name"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Fatalf("want %v, got %v", exp, names)
+	}
+}
+
+func TestView_Invalid_Struct(t *testing.T) {
+	test := []string{"fail", "fail2"}
+	ext := New(&test)
+
+	if _, err := ext.ForInsert("db").Names(); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+	if _, err := ext.ForUpdate("db").FieldValueMap(); err == nil {
+		t.Fatal("Passed value is not a valid struct")
+	}
+}